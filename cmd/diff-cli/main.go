@@ -0,0 +1,115 @@
+// Command diff-cli diffs two files from the shell.
+//
+//	diff-cli [-timeout 1s] [-html | -unified] fileA fileB
+//
+// The diff is printed to stdout in color, or as HTML or a unified diff.  Like
+// diff(1) the exit status is 0 when the files are the same, 1 when they
+// differ and 2 when something went wrong.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dknieriem/diff_live/diff"
+)
+
+// Files larger than this together are diffed line by line, character diffs
+// of them take too long to be of use.
+const lineModeBytes = 1 << 16
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Parse the arguments, diff the files and write the result, returning the
+// exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff-cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: diff-cli [flags] fileA fileB")
+		flags.PrintDefaults()
+	}
+	timeout := flags.Duration("timeout", time.Second, "time to spend on the diff, 0 for no limit")
+	html := flags.Bool("html", false, "print the diff as HTML")
+	unified := flags.Bool("unified", false, "print the diff in the unified format of diff -u")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 || (*html && *unified) {
+		flags.Usage()
+		return 2
+	}
+	fileA, fileB := flags.Arg(0), flags.Arg(1)
+
+	dmp := diff.New()
+	dmp.Diff_Timeout = *timeout
+	diffs, err := diffFiles(dmp, fileA, fileB)
+	if err != nil {
+		fmt.Fprintf(stderr, "diff-cli: %s\n", err)
+		return 2
+	}
+	if len(diffs) == 0 || (len(diffs) == 1 && diffs[0].Type == diff.EQUAL) {
+		return 0
+	}
+
+	switch {
+	case *html:
+		_, err = io.WriteString(stdout, diff.DiffPrettyHtml(diffs))
+	case *unified:
+		_, err = io.WriteString(stdout, diff.DiffToUnifiedDiff(diffs, fileA, fileB, 3))
+	default:
+		_, err = io.WriteString(stdout, diff.DiffPrettyText(diffs))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "diff-cli: %s\n", err)
+		return 2
+	}
+	return 1
+}
+
+// Diff two files, character by character and cleaned up for reading, or
+// line by line when they are large.
+func diffFiles(dmp *diff.DiffMatchPatch, fileA, fileB string) ([]diff.Diff, error) {
+	a, err := os.Open(fileA)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Close()
+	b, err := os.Open(fileB)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	infoA, err := a.Stat()
+	if err != nil {
+		return nil, err
+	}
+	infoB, err := b.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if infoA.IsDir() || infoB.IsDir() {
+		return nil, errors.New("cannot diff directories")
+	}
+	if infoA.Size()+infoB.Size() > lineModeBytes {
+		return dmp.DiffReaders(a, b)
+	}
+
+	textA, err := io.ReadAll(a)
+	if err != nil {
+		return nil, err
+	}
+	textB, err := io.ReadAll(b)
+	if err != nil {
+		return nil, err
+	}
+	diffs := dmp.DiffMainStrings(string(textA), string(textB), false)
+	return dmp.DiffCleanupSemantic(diffs), nil
+}
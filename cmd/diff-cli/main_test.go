@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Args []string
+
+		ExpectedStatus int
+		ExpectedStdout string
+		ExpectedStderr string
+	}
+
+	dir := t.TempDir()
+	write := func(name, text string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(text), 0o644))
+		return path
+	}
+	same := write("same.txt", "The cat sat.\n")
+	sameCopy := write("same-copy.txt", "The cat sat.\n")
+	changed := write("changed.txt", "The dog sat.\n")
+	missing := filepath.Join(dir, "missing.txt")
+
+	for i, tc := range []TestCase{
+		{"Same files", []string{same, sameCopy}, 0, "", ""},
+		{"Different files", []string{same, changed}, 1, "The \x1b[31mcat\x1b[0m\x1b[32mdog\x1b[0m sat.\n", ""},
+		{"HTML", []string{"-html", same, changed}, 1, "<span>The </span><del style=\"background:#ffe6e6;\">cat</del><ins style=\"background:#e6ffe6;\">dog</ins><span> sat.&para;<br></span>", ""},
+		{
+			"Unified", []string{"-unified", same, changed}, 1,
+			"--- " + same + "\n+++ " + changed + "\n@@ -1 +1 @@\n-The cat sat.\n+The dog sat.\n", "",
+		},
+		{"Missing file", []string{same, missing}, 2, "", "diff-cli: open " + missing + ": no such file or directory\n"},
+		{"Directory", []string{same, dir}, 2, "", "diff-cli: cannot diff directories\n"},
+		{"One file", []string{same}, 2, "", "usage: diff-cli [flags] fileA fileB\n"},
+		{"HTML and unified", []string{"-html", "-unified", same, changed}, 2, "", "usage: diff-cli [flags] fileA fileB\n"},
+		{"Unknown flag", []string{"-color", same, changed}, 2, "", "flag provided but not defined: -color\n"},
+		{"Bad timeout", []string{"-timeout", "soon", same, changed}, 2, "", "invalid value \"soon\" for flag -timeout"},
+	} {
+		var stdout, stderr bytes.Buffer
+		status := run(tc.Args, &stdout, &stderr)
+		assert.Equal(t, tc.ExpectedStatus, status, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedStdout, stdout.String(), fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.True(t, strings.HasPrefix(stderr.String(), tc.ExpectedStderr), fmt.Sprintf("Test case #%d, %s: %q", i, tc.Name, stderr.String()))
+	}
+
+	// Files too large for a character diff are diffed line by line.
+	var textA, textB strings.Builder
+	for n := 0; textA.Len() <= lineModeBytes; n++ {
+		fmt.Fprintf(&textA, "line %d\n", n)
+		if n == 500 {
+			fmt.Fprintf(&textB, "line five hundred\n")
+		} else {
+			fmt.Fprintf(&textB, "line %d\n", n)
+		}
+	}
+	largeA := write("large-a.txt", textA.String())
+	largeB := write("large-b.txt", textB.String())
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-unified", largeA, largeB}, &stdout, &stderr)
+	assert.Equal(t, 1, status)
+	assert.Empty(t, stderr.String())
+	assert.Contains(t, stdout.String(), "\n-line 500\n+line five hundred\n")
+	assert.Equal(t, 0, run([]string{largeA, largeA}, &stdout, &stderr))
+}
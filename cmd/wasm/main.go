@@ -1,62 +1,285 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"syscall/js"
+	"time"
 
-	"github.com/dknieriem/diff_live/cmd/wasm/diff"
+	"github.com/dknieriem/diff_live/diff"
 )
 
 // * Go:
 // * diff_main needs text1, text2, opt_checklines (default true), opt_deadline (default to 1 sec)
 
+// Every function exposed to JS returns a Promise, which rejects with an Error
+// on bad arguments or a failed diff.
+
+// The settings of every diff, changed by configureDiff.
+var settings = diff.New()
+
+// Read (inputA, inputB[, timeout]) from JS - timeout is in seconds, defaults
+// to the configured one, and 0 means no timeout.
+func parseDiffArgs(args []js.Value) (*diff.DiffMatchPatch, string, string, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, "", "", errors.New("Invalid no. of arguments passed - 2 or 3 required")
+	}
+	dmp := settings.Clone()
+	if len(args) == 3 && !args[2].IsUndefined() {
+		if args[2].Type() != js.TypeNumber || math.IsNaN(args[2].Float()) || args[2].Float() < 0 {
+			return nil, "", "", errors.New("Invalid timeout - a number of seconds >= 0 required")
+		}
+		dmp.Diff_Timeout = time.Duration(args[2].Float() * float64(time.Second))
+	}
+	return dmp, args[0].String(), args[1].String(), nil
+}
+
+// Diff the inputs and clean the result up for display
+func computeDiffs(dmp *diff.DiffMatchPatch, inputA, inputB string) ([]diff.Diff, error) {
+	diffs := dmp.DiffMainProgress([]rune(inputA), []rune(inputB), true, yielder())
+	diffs = dmp.DiffCleanupSemantic(diffs)
+	diffs = dmp.DiffCleanupEfficiency(diffs)
+	return diffs, nil
+}
+
+// How long a diff may keep the thread of the page before it lets the
+// browser handle events and paint.
+const yieldInterval = 50 * time.Millisecond
+
+// A progress function for DiffMainProgress which hands the thread back to
+// the browser every yieldInterval.  The bisect never blocks on its own, so
+// without this a long diff would freeze the page until it is done: sleeping
+// leaves every goroutine waiting, and the Go scheduler then returns to the
+// JS event loop until the timer fires.
+func yielder() func(done, total int) {
+	last := time.Now()
+	return func(done, total int) {
+		if time.Since(last) >= yieldInterval {
+			time.Sleep(time.Millisecond)
+			last = time.Now()
+		}
+	}
+}
+
+// Diff the inputs and render the result as HTML, without touching the document
+func computeHtml(dmp *diff.DiffMatchPatch, inputA, inputB string) (string, error) {
+	diffs, err := computeDiffs(dmp, inputA, inputB)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse JSON. Error %s occurred\n", err)
+	}
+	return diff.DiffPrettyHtml(diffs), nil
+}
+
+// Run work for a JS function and return a Promise which resolves with its
+// result, or rejects with an Error holding its error.
+func promise(work func() (any, error)) js.Value {
+	return newPromise(func(resolve, reject js.Value) {
+		result, err := work()
+		settle(resolve, reject, result, err)
+	})
+}
+
+// promise running work in its own goroutine, so the JS function returns at
+// once and the Promise settles when the work is done.  Go still runs on the
+// thread of the page, which the scheduler hands back to the browser whenever
+// all goroutines wait, so long work has to wait now and then, as the diffs
+// do through yielder.
+func goPromise(work func() (any, error)) js.Value {
+	return newPromise(func(resolve, reject js.Value) {
+		go func() {
+			result, err := work()
+			settle(resolve, reject, result, err)
+		}()
+	})
+}
+
+// Create a Promise, start calls the resolve and reject functions of it.
+func newPromise(start func(resolve, reject js.Value)) js.Value {
+	executor := js.FuncOf(func(this js.Value, args []js.Value) any {
+		start(args[0], args[1])
+		return nil
+	})
+	// The executor runs before the Promise constructor returns.
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+func settle(resolve, reject js.Value, result any, err error) {
+	if err != nil {
+		reject.Invoke(js.Global().Get("Error").New(err.Error()))
+		return
+	}
+	resolve.Invoke(result)
+}
+
 // Added function to wrap the diff call for js exposure
+// diffStrings(inputA, inputB[, timeout]) writes the HTML diff into #diffoutput,
+// this is the only function which needs the document
 func diffWrapper() js.Func {
 	diffFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
-		if len(args) != 2 {
-			result := map[string]any{
-				"error": "Invalid no. of arguments passed - 2 required",
+		// The settings are read now, a later configureDiff doesn't apply.
+		dmp, inputA, inputB, err := parseDiffArgs(args)
+		return goPromise(func() (any, error) {
+			if err != nil {
+				return nil, err
 			}
-			return result
-		}
-		jsDoc := js.Global().Get("document")
-		if !jsDoc.Truthy() {
-			result := map[string]any{
-				"error": "Unable to get document object",
+			htmlDiff, err := computeHtml(dmp, inputA, inputB)
+			if err != nil {
+				return nil, err
 			}
-			return result
-		}
-		DiffResultArea := jsDoc.Call("getElementById", "diffoutput")
-		if !DiffResultArea.Truthy() {
-			result := map[string]any{
-				"error": "Unable to get output text area #diffoutput",
+			jsDoc := js.Global().Get("document")
+			if !jsDoc.Truthy() {
+				return nil, errors.New("Unable to get document object")
 			}
-			return result
-		}
-		inputA := args[0].String()
-		inputB := args[1].String()
-		fmt.Printf("inputA %s\n", inputA)
-		fmt.Printf("inputB %s\n", inputB)
-		dmp := new(diff.DiffMatchPatch) //.New()
-		err, diffs := dmp.DiffRecurse(inputA, inputB)
-		if err != nil {
-			errStr := fmt.Sprintf("unable to parse JSON. Error %s occurred\n", err)
-			result := map[string]any{
-				"error": errStr,
-			}
-			return result
-		}
-		diffs = dmp.DiffCleanupSemantic(diffs)
-		diffs = dmp.DiffCleanupEfficiency(diffs)
-		htmlDiff := diff.DiffPrettyHtml(diffs)
-		DiffResultArea.Set("innerHTML", htmlDiff)
-		return nil
+			DiffResultArea := jsDoc.Call("getElementById", "diffoutput")
+			if !DiffResultArea.Truthy() {
+				return nil, errors.New("Unable to get output text area #diffoutput")
+			}
+			DiffResultArea.Set("innerHTML", htmlDiff)
+			return nil, nil
+		})
 	})
 	return diffFunc
 }
 
+// diffCompute(inputA, inputB[, timeout]) resolves to the HTML diff as a
+// string, so it also works in a Web Worker where there is no document.
+func diffComputeWrapper() js.Func {
+	diffFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		dmp, inputA, inputB, err := parseDiffArgs(args)
+		return goPromise(func() (any, error) {
+			if err != nil {
+				return nil, err
+			}
+			return computeHtml(dmp, inputA, inputB)
+		})
+	})
+	return diffFunc
+}
+
+// diffStructured(inputA, inputB[, timeout]) resolves to the diffs as an
+// array of {op: "insert"|"delete"|"equal", text: "..."} objects, without
+// touching the document.
+func diffStructuredWrapper() js.Func {
+	diffFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		dmp, inputA, inputB, err := parseDiffArgs(args)
+		return goPromise(func() (any, error) {
+			if err != nil {
+				return nil, err
+			}
+			diffs, err := computeDiffs(dmp, inputA, inputB)
+			if err != nil {
+				return nil, err
+			}
+			result := make([]any, 0, len(diffs))
+			for _, d := range diffs {
+				result = append(result, map[string]any{
+					"op":   opName(d.Type),
+					"text": d.Text,
+				})
+			}
+			return result, nil
+		})
+	})
+	return diffFunc
+}
+
+// Read a number >= 0 from options[name], if it is set.
+func optionalNumber(options js.Value, name string) (float64, bool, error) {
+	value := options.Get(name)
+	if value.IsUndefined() {
+		return 0, false, nil
+	}
+	if value.Type() != js.TypeNumber || math.IsNaN(value.Float()) || value.Float() < 0 {
+		return 0, false, fmt.Errorf("Invalid %s - a number >= 0 required", name)
+	}
+	return value.Float(), true, nil
+}
+
+// Apply configureDiff({timeout, editCost, matchThreshold}) to settings.  The
+// timeout is in seconds, the threshold is clamped to 0..1.  Nothing changes
+// unless all the options are valid.
+func configure(args []js.Value) error {
+	if len(args) != 1 || args[0].Type() != js.TypeObject {
+		return errors.New("Invalid arguments passed - an object of options required")
+	}
+	options := args[0]
+	configured := settings.Clone()
+
+	timeout, ok, err := optionalNumber(options, "timeout")
+	if err != nil {
+		return err
+	}
+	if ok {
+		configured.Diff_Timeout = time.Duration(timeout * float64(time.Second))
+	}
+	editCost, ok, err := optionalNumber(options, "editCost")
+	if err != nil {
+		return err
+	}
+	if ok {
+		if editCost > math.MaxUint16 {
+			return fmt.Errorf("Invalid editCost - at most %d", math.MaxUint16)
+		}
+		configured.Diff_EditCost = uint16(math.Round(editCost))
+	}
+	matchThreshold, ok, err := optionalNumber(options, "matchThreshold")
+	if err != nil {
+		return err
+	}
+	if ok {
+		configured.Match_Threshold = float32(min(matchThreshold, 1))
+	}
+
+	settings = configured
+	return nil
+}
+
+// configureDiff({timeout, editCost, matchThreshold}) changes the settings of
+// the following diffs, any option may be left out.
+func configureWrapper() js.Func {
+	configureFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		return promise(func() (any, error) {
+			return nil, configure(args)
+		})
+	})
+	return configureFunc
+}
+
+// Wrap an offset mapping of diff as a JS function of (text, offset), for
+// runeOffsetToUTF16 and utf16OffsetToRune.
+func offsetWrapper(convert func(string, int) int) js.Func {
+	offsetFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
+		return promise(func() (any, error) {
+			if len(args) != 2 || args[0].Type() != js.TypeString || args[1].Type() != js.TypeNumber {
+				return nil, errors.New("Invalid arguments passed - a string and a number required")
+			}
+			return convert(args[0].String(), args[1].Int()), nil
+		})
+	})
+	return offsetFunc
+}
+
+func opName(op diff.Operation) string {
+	switch op {
+	case diff.INSERT, diff.MOVE:
+		// A move is inserted here and deleted where it came from.
+		return "insert"
+	case diff.DELETE:
+		return "delete"
+	default:
+		return "equal"
+	}
+}
+
 func main() {
 	fmt.Println("Go Web Assembly")
 	js.Global().Set("diffStrings", diffWrapper())
+	js.Global().Set("diffCompute", diffComputeWrapper())
+	js.Global().Set("diffStructured", diffStructuredWrapper())
+	js.Global().Set("configureDiff", configureWrapper())
+	js.Global().Set("runeOffsetToUTF16", offsetWrapper(diff.RuneOffsetToUTF16))
+	js.Global().Set("utf16OffsetToRune", offsetWrapper(diff.UTF16OffsetToRune))
 	<-make(chan struct{})
 }
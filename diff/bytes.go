@@ -0,0 +1,20 @@
+package diff
+
+import "context"
+
+// DiffMainBytes diffs two byte slices byte by byte, for ASCII protocols and
+// other binary-ish data where decoding runes is pure overhead.  The texts of
+// the diffs are the original byte substrings.
+// This is not safe for multi-byte UTF-8: an edit may split a rune, leaving
+// invalid UTF-8 in the diffs, and the rune based functions such as the
+// cleanups and DiffToDelta don't expect that.  Use DiffMain for text.
+func (dmp *DiffMatchPatch) DiffMainBytes(textA, textB []byte) []Diff {
+	ctx, cancel := dmp.timeoutContext(context.Background())
+	defer cancel()
+
+	var diffs []Diff
+	for _, aDiff := range diffSlices(ctx, textA, textB, dmp.bisectCheckInterval()) {
+		diffs = append(diffs, Diff{aDiff.Type, string(aDiff.Tokens)})
+	}
+	return diffs
+}
@@ -0,0 +1,69 @@
+package diff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMainBytes(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+
+		Expected []Diff
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", nil},
+		{"Equality", "abc", "abc", []Diff{{EQUAL, "abc"}}},
+		{"Simple insertion", "abc", "ab123c", []Diff{{EQUAL, "ab"}, {INSERT, "123"}, {EQUAL, "c"}}},
+		{"Simple deletion", "a123bc", "abc", []Diff{{EQUAL, "a"}, {DELETE, "123"}, {EQUAL, "bc"}}},
+		{"Two insertions", "abc", "a123b456c", []Diff{{EQUAL, "a"}, {INSERT, "123"}, {EQUAL, "b"}, {INSERT, "456"}, {EQUAL, "c"}}},
+		{"Simple case", "a", "b", []Diff{{DELETE, "a"}, {INSERT, "b"}}},
+		{"Bisection", "cat", "map", []Diff{{DELETE, "c"}, {INSERT, "m"}, {EQUAL, "a"}, {DELETE, "t"}, {INSERT, "p"}}},
+		{"Overlap", "1ayb2", "abxab", []Diff{{DELETE, "1"}, {EQUAL, "a"}, {DELETE, "y"}, {EQUAL, "b"}, {DELETE, "2"}, {INSERT, "xab"}}},
+		// é and è share their leading byte.
+		{"Splits runes", "é", "è", []Diff{{EQUAL, "\xc3"}, {DELETE, "\xa9"}, {INSERT, "\xa8"}}},
+		{"Invalid UTF-8", "\xff\x00\xfe", "\xff\xfe", []Diff{{EQUAL, "\xff"}, {DELETE, "\x00"}, {EQUAL, "\xfe"}}},
+	} {
+		actual := dmp.DiffMainBytes([]byte(tc.TextA), []byte(tc.TextB))
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// The bytes are kept as they are.
+	runesA, runesB := randomEditedText(5000, 100)
+	textA := []byte(string(runesA))
+	textB := []byte(string(runesB))
+	textB[10] = 0xff
+	diffs := dmp.DiffMainBytes(textA, textB)
+	assert.Equal(t, string(textA), dmp.DiffTextSource(diffs))
+	assert.Equal(t, string(textB), dmp.DiffTextResult(diffs))
+}
+
+func BenchmarkDiffMainBytes(b *testing.B) {
+	// 64KB of ASCII with a few hundred edits.
+	runesA, runesB := randomEditedText(1<<16, 200)
+	dmp := New()
+	dmp.Diff_Timeout = 0
+
+	b.Run("Bytes", func(b *testing.B) {
+		textA, textB := []byte(string(runesA)), []byte(string(runesB))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmp.DiffMainBytes(textA, textB)
+		}
+	})
+	b.Run("Runes", func(b *testing.B) {
+		textA, textB := string(runesA), string(runesB)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmp.DiffMainStrings(textA, textB, false)
+		}
+	})
+}
@@ -0,0 +1,461 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type Diff struct {
+	Type Operation
+	Text string
+}
+
+// String renders the diff for debugging, e.g. Diff(INSERT,"a¶b") with
+// newlines shown as pilcrows.
+func (diff Diff) String() string {
+	outStr := strings.ReplaceAll(diff.Text, "\n", "\u00b6")
+	outStr = "Diff(" + diff.Type.String() + ",\"" + outStr + "\")"
+	return outStr
+}
+
+// Equal reports whether both diffs have the same type and text.
+func (diff *Diff) Equal(other Diff) bool {
+	return diff.Type == other.Type && diff.Text == other.Text
+}
+
+// DiffEqual reports whether two diff lists are equal element by element.
+func DiffEqual(diffsA, diffsB []Diff) bool {
+	if len(diffsA) != len(diffsB) {
+		return false
+	}
+	for i := range diffsA {
+		if !diffsA[i].Equal(diffsB[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffsClone returns an independent copy of diffs.  The cleanup functions
+// change their input in place, run them on a clone to keep the original.
+func DiffsClone(diffs []Diff) []Diff {
+	if diffs == nil {
+		return nil
+	}
+	clone := make([]Diff, len(diffs))
+	copy(clone, diffs)
+	return clone
+}
+
+// DiffCanonicalize returns diffs in a canonical form, so that two diffs of
+// the same edits compare equal with DiffEqual whichever code path built
+// them.  Within each run of edits between two equalities all deletions come
+// first, joined into one, followed by the insertions, with neighbouring
+// insertions of the same type joined.  Adjacent equalities are joined and
+// empty diffs dropped.  The texts are left as they are: unlike
+// DiffCleanupMerge no common prefix or suffix is factored out and no edit is
+// shifted, so source and result text are kept.  diffs is not modified.
+func DiffCanonicalize(diffs []Diff) []Diff {
+	canonical := make([]Diff, 0, len(diffs))
+	var deleted strings.Builder
+	var inserted []Diff
+	flush := func() {
+		if deleted.Len() > 0 {
+			canonical = append(canonical, Diff{DELETE, deleted.String()})
+			deleted.Reset()
+		}
+		canonical = append(canonical, inserted...)
+		inserted = inserted[:0]
+	}
+
+	for _, aDiff := range diffs {
+		if len(aDiff.Text) == 0 {
+			continue
+		}
+		switch aDiff.Type {
+		case DELETE:
+			_, _ = deleted.WriteString(aDiff.Text)
+		case INSERT, MOVE:
+			// A MOVE stays apart from the insertions around it.
+			if n := len(inserted); n > 0 && inserted[n-1].Type == aDiff.Type {
+				inserted[n-1].Text += aDiff.Text
+			} else {
+				inserted = append(inserted, aDiff)
+			}
+		default:
+			flush()
+			if n := len(canonical); n > 0 && canonical[n-1].Type == aDiff.Type {
+				canonical[n-1].Text += aDiff.Text
+			} else {
+				canonical = append(canonical, aDiff)
+			}
+		}
+	}
+	flush()
+	return canonical
+}
+
+// DiffEdits iterates over the diffs which change the text, skipping the
+// equalities.
+func DiffEdits(diffs []Diff) iter.Seq[Diff] {
+	return func(yield func(Diff) bool) {
+		for _, aDiff := range diffs {
+			if aDiff.Type.IsEdit() && !yield(aDiff) {
+				return
+			}
+		}
+	}
+}
+
+// DiffStats counts the inserted, deleted and unchanged runes in diffs.
+func DiffStats(diffs []Diff) (inserted, deleted, unchanged int) {
+	for _, aDiff := range diffs {
+		n := utf8.RuneCountInString(aDiff.Text)
+		switch aDiff.Type {
+		case INSERT, MOVE:
+			inserted += n
+		case DELETE:
+			deleted += n
+		case EQUAL:
+			unchanged += n
+		}
+	}
+	return inserted, deleted, unchanged
+}
+
+// DiffChangeRatio returns the fraction of runes in diffs which were inserted
+// or deleted, from 0 for identical texts to 1 for nothing in common.
+// An empty diff has a ratio of 0.
+func DiffChangeRatio(diffs []Diff) float64 {
+	inserted, deleted, unchanged := DiffStats(diffs)
+	changed := inserted + deleted
+	if changed+unchanged == 0 {
+		return 0
+	}
+	return float64(changed) / float64(changed+unchanged)
+}
+
+func (dmp *DiffMatchPatch) DiffTextSource(diffs []Diff) string {
+	//StringBuilder text = new StringBuilder()
+	var text bytes.Buffer
+
+	for _, aDiff := range diffs {
+		if aDiff.Type != INSERT && aDiff.Type != MOVE {
+			_, _ = text.WriteString(aDiff.Text)
+		}
+	}
+	return text.String()
+}
+
+func (dmp *DiffMatchPatch) DiffTextResult(diffs []Diff) string {
+	var text bytes.Buffer
+
+	for _, aDiff := range diffs {
+		if aDiff.Type != DELETE {
+			_, _ = text.WriteString(aDiff.Text)
+		}
+	}
+	return text.String()
+}
+
+// DiffTextSourceLen returns the length in runes of DiffTextSource, without
+// building the text.
+func (dmp *DiffMatchPatch) DiffTextSourceLen(diffs []Diff) int {
+	_, deleted, unchanged := DiffStats(diffs)
+	return deleted + unchanged
+}
+
+// DiffTextResultLen returns the length in runes of DiffTextResult, without
+// building the text.
+func (dmp *DiffMatchPatch) DiffTextResultLen(diffs []Diff) int {
+	inserted, _, unchanged := DiffStats(diffs)
+	return inserted + unchanged
+}
+
+// DiffIndexSourceToTarget maps the rune offset loc in the source text to
+// the equivalent offset in the target text.  A location inside a deletion
+// maps to where the following text starts, a location past the end of the
+// source maps to the end of the target.
+func (dmp *DiffMatchPatch) DiffIndexSourceToTarget(diffs []Diff, loc int) int {
+	return diffIndex(diffs, loc, DELETE)
+}
+
+// DiffIndexTargetToSource maps the rune offset loc in the target text back
+// to the source text, the reverse of DiffIndexSourceToTarget.  A location
+// inside an insertion maps to where the following text starts.
+func (dmp *DiffMatchPatch) DiffIndexTargetToSource(diffs []Diff, loc int) int {
+	return diffIndex(diffs, loc, INSERT)
+}
+
+// Map loc from one text of diffs to the other, op is the edit which only
+// exists in the text loc comes from.  Edits at loc itself are skipped, as
+// DiffXIndex does.
+func diffIndex(diffs []Diff, loc int, op Operation) int {
+	loc = max(loc, 0)
+	from, to := 0, 0
+	for _, aDiff := range diffs {
+		textLen := utf8.RuneCountInString(aDiff.Text)
+		diffType := aDiff.Type
+		if diffType == MOVE {
+			diffType = INSERT
+		}
+		switch diffType {
+		case EQUAL:
+			if loc < from+textLen {
+				return to + (loc - from)
+			}
+			from += textLen
+			to += textLen
+		case op:
+			if loc < from+textLen {
+				// The location was removed.
+				return to
+			}
+			from += textLen
+		default:
+			to += textLen
+		}
+	}
+	return to
+}
+
+// DiffValidate checks that diffs turn textA into textB, that is their source
+// text is textA and their result text is textB.  The error gives the rune
+// offset of the first difference.
+func (dmp *DiffMatchPatch) DiffValidate(diffs []Diff, textA, textB string) error {
+	if source := dmp.DiffTextSource(diffs); source != textA {
+		return fmt.Errorf("diff source text differs from textA at rune %d", firstMismatch(source, textA))
+	}
+	if result := dmp.DiffTextResult(diffs); result != textB {
+		return fmt.Errorf("diff result text differs from textB at rune %d", firstMismatch(result, textB))
+	}
+	return nil
+}
+
+// DiffApply builds the result text of diffs from textA, their source text.
+// Unlike DiffTextResult it checks the equalities and deletions against
+// textA as it goes, and the error gives the rune offset of textA where they
+// first differ.  It is a lighter alternative to patches when both the exact
+// diff and its source are at hand.
+func (dmp *DiffMatchPatch) DiffApply(textA string, diffs []Diff) (string, error) {
+	var text strings.Builder
+	// The byte and rune offsets of textA reached so far.
+	pos, runePos := 0, 0
+
+	for _, aDiff := range diffs {
+		switch aDiff.Type {
+		case INSERT, MOVE:
+			_, _ = text.WriteString(aDiff.Text)
+			continue
+		case EQUAL:
+			_, _ = text.WriteString(aDiff.Text)
+		}
+		if !strings.HasPrefix(textA[pos:], aDiff.Text) {
+			return "", fmt.Errorf("diff source text differs from textA at rune %d", runePos+firstMismatch(textA[pos:], aDiff.Text))
+		}
+		pos += len(aDiff.Text)
+		runePos += utf8.RuneCountInString(aDiff.Text)
+	}
+	if pos < len(textA) {
+		return "", fmt.Errorf("diff source text differs from textA at rune %d", runePos)
+	}
+	return text.String(), nil
+}
+
+// The rune offset of the first difference between two texts.
+func firstMismatch(textA, textB string) int {
+	i := 0
+	for i < len(textA) && i < len(textB) && textA[i] == textB[i] {
+		i++
+	}
+	// Back up to the start of a rune both texts share the first bytes of.
+	for i > 0 && i < len(textA) && !utf8.RuneStart(textA[i]) {
+		i--
+	}
+	return utf8.RuneCountInString(textA[:i])
+}
+
+// An Edit replaces Delete runes of a text at the rune offset Pos with the
+// text Insert.
+type Edit struct {
+	Pos    int
+	Delete int
+	Insert string
+}
+
+// DiffEditScript turns diffs into edits at absolute rune offsets of the
+// source text, for systems which apply edits by offset.  The edits are in
+// order and don't overlap, each deletion and the insertion next to it make
+// one edit.  All positions are in the source text, so apply the edits from
+// last to first, or shift each by the length change of those before it.
+func DiffEditScript(diffs []Diff) []Edit {
+	var edits []Edit
+	pos := 0
+	// The edit being built, if its Delete or Insert is non-empty.
+	edit := Edit{}
+	var insert strings.Builder
+	flush := func() {
+		edit.Insert = insert.String()
+		if edit.Delete > 0 || len(edit.Insert) > 0 {
+			edits = append(edits, edit)
+		}
+		insert.Reset()
+		edit = Edit{Pos: pos}
+	}
+	for _, aDiff := range diffs {
+		switch aDiff.Type {
+		case EQUAL:
+			flush()
+			pos += utf8.RuneCountInString(aDiff.Text)
+			edit.Pos = pos
+		case DELETE:
+			n := utf8.RuneCountInString(aDiff.Text)
+			edit.Delete += n
+			pos += n
+		case INSERT, MOVE:
+			_, _ = insert.WriteString(aDiff.Text)
+		}
+	}
+	flush()
+	return edits
+}
+
+// * diff_toDelta
+// Crush the diff into an encoded string which describes the operations
+// required to transform text1 into text2.
+// E.g. =3\t-2\t+ing  -> Keep 3 chars, delete 2 chars, insert 'ing'.
+// Operations are tab-separated.  Inserted text is escaped using %xx notation.
+// Counts are in runes.
+func (dmp *DiffMatchPatch) DiffToDelta(diffs []Diff) string {
+	var text bytes.Buffer
+	for i, aDiff := range diffs {
+		if i > 0 {
+			_, _ = text.WriteString("\t")
+		}
+		switch aDiff.Type {
+		case INSERT, MOVE:
+			_, _ = text.WriteString("+")
+			_, _ = text.WriteString(encodeURI(aDiff.Text))
+		case DELETE:
+			_, _ = text.WriteString("-")
+			_, _ = text.WriteString(strconv.Itoa(utf8.RuneCountInString(aDiff.Text)))
+		case EQUAL:
+			_, _ = text.WriteString("=")
+			_, _ = text.WriteString(strconv.Itoa(utf8.RuneCountInString(aDiff.Text)))
+		}
+	}
+	return text.String()
+}
+
+// * diff_fromDelta
+// Given the original text1, and an encoded string which describes the
+// operations required to transform text1 into text2, compute the full diff.
+func (dmp *DiffMatchPatch) DiffFromDelta(text1, delta string) ([]Diff, error) {
+	diffs := []Diff{}
+	runes := []rune(text1)
+	pointer := 0 // Cursor in text1
+	for _, token := range strings.Split(delta, "\t") {
+		if len(token) == 0 {
+			// Blank tokens are ok (from a trailing \t).
+			continue
+		}
+
+		// Each token begins with a one character parameter which specifies the
+		// operation of this token (delete, insert, equality).
+		param := token[1:]
+		switch op := token[0]; op {
+		case '+':
+			line, err := decodeURI(param)
+			if err != nil {
+				return nil, fmt.Errorf("illegal escape in delta: %q: %w", param, err)
+			}
+			diffs = append(diffs, Diff{INSERT, line})
+		case '=', '-':
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number in delta: %q", param)
+			} else if n < 0 {
+				return nil, fmt.Errorf("negative number in delta: %q", param)
+			} else if n > len(runes)-pointer {
+				return nil, fmt.Errorf("delta token %q at rune %d exceeds source text length (%d)", token, pointer, len(runes))
+			}
+
+			text := string(runes[pointer : pointer+n])
+			pointer += n
+			if op == '=' {
+				diffs = append(diffs, Diff{EQUAL, text})
+			} else {
+				diffs = append(diffs, Diff{DELETE, text})
+			}
+		default:
+			// Anything else is an error.
+			return nil, fmt.Errorf("invalid diff operation in delta: %q", token)
+		}
+	}
+
+	if pointer != len(runes) {
+		return nil, fmt.Errorf("delta length (%d) does not equal source text length (%d)", pointer, len(runes))
+	}
+	return diffs, nil
+}
+
+// jsonDiff is the serialized form of a Diff.  The op names are part of the
+// format, unlike the Operation values.
+type jsonDiff struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffToJSON encodes diffs as a JSON array of {"op": ..., "text": ...}
+// objects, where op is one of "delete", "insert", "equal" or "move".
+func DiffToJSON(diffs []Diff) ([]byte, error) {
+	out := make([]jsonDiff, 0, len(diffs))
+	for _, aDiff := range diffs {
+		var op string
+		switch aDiff.Type {
+		case DELETE:
+			op = "delete"
+		case INSERT:
+			op = "insert"
+		case EQUAL:
+			op = "equal"
+		case MOVE:
+			op = "move"
+		default:
+			return nil, fmt.Errorf("invalid diff operation: %d", aDiff.Type)
+		}
+		out = append(out, jsonDiff{op, aDiff.Text})
+	}
+	return json.Marshal(out)
+}
+
+// DiffFromJSON decodes diffs encoded by DiffToJSON.
+func DiffFromJSON(data []byte) ([]Diff, error) {
+	var in []jsonDiff
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	diffs := make([]Diff, 0, len(in))
+	for _, aDiff := range in {
+		var op Operation
+		switch aDiff.Op {
+		case "delete":
+			op = DELETE
+		case "insert":
+			op = INSERT
+		case "equal":
+			op = EQUAL
+		case "move":
+			op = MOVE
+		default:
+			return nil, fmt.Errorf("invalid diff operation in JSON: %q", aDiff.Op)
+		}
+		diffs = append(diffs, Diff{op, aDiff.Text})
+	}
+	return diffs, nil
+}
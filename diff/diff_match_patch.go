@@ -0,0 +1,2638 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DiffMatchPatch holds the settings of the diff, match and patch functions.
+// The methods only read the settings and keep all other state local to the
+// call, so one DiffMatchPatch can be used by many goroutines at once as long
+// as nobody changes its fields meanwhile.  To change settings for one
+// goroutine, change a Clone.
+type DiffMatchPatch struct {
+	// Defaults.
+	// Set these on your diff_match_patch instance to override the defaults.
+
+	// Number of seconds to map a diff before giving up (0 for infinity).
+	Diff_Timeout time.Duration
+	// Cost of an empty edit operation in terms of edit characters.
+	Diff_EditCost uint16
+	// Diff the two halves of large bisections in parallel goroutines.
+	Diff_Parallel bool
+	// Stop refining the diff once it holds about this many diffs, the rest of
+	// the texts becomes one coarse deletion and insertion (0 for no limit).
+	// The result may still be a few diffs longer than this.
+	Diff_MaxDiffs int
+	// How many bisect iterations to run between checks of Diff_Timeout and
+	// cancellation (0 for the default of 256).  Lower gives up sooner after
+	// the deadline, higher spends less time checking it.
+	Diff_CheckInterval int
+	// At what point is no match declared (0.0 = perfection, 1.0 = very loose).
+	Match_Threshold float32
+	// How far to search for a match (0 = exact location, 1000+ = broad match).
+	// A match this many characters away from the expected location will add
+	// 1.0 to the score (0.0 is a perfect match).
+	Match_Distance int32
+	// When deleting a large block of text (over ~64 characters), how close does
+	// the contents have to match the expected contents. (0.0 = perfection,
+	// 1.0 = very loose).  Note that Match_Threshold controls how closely the
+	// end points of a delete need to match.
+	Patch_DeleteThreshold float32
+	// Chunk size for context length.
+	Patch_Margin uint16
+
+	// The longest pattern in runes which the match functions search
+	// fuzzily, and so the longest stretch of source text a patch matches
+	// in one piece.  The bitap keeps a pattern in the bits of an int, so 0,
+	// or any larger value, means the bits of an int.
+	Match_MaxBits uint16
+}
+
+func New() *DiffMatchPatch {
+	// Defaults.
+	return &DiffMatchPatch{
+		Diff_Timeout:          time.Second,
+		Diff_EditCost:         4,
+		Match_Threshold:       0.5,
+		Match_Distance:        1000,
+		Patch_DeleteThreshold: 0.5,
+		Patch_Margin:          4,
+		Match_MaxBits:         32,
+	}
+}
+
+// Clone returns a copy of dmp with the same settings, which can be changed
+// without affecting dmp.
+func (dmp *DiffMatchPatch) Clone() *DiffMatchPatch {
+	clone := *dmp
+	return &clone
+}
+
+// An Option configures a DiffMatchPatch created by NewWithOptions.
+type Option func(*DiffMatchPatch)
+
+// NewWithOptions returns a DiffMatchPatch with the New defaults, then applies
+// opts in order.
+func NewWithOptions(opts ...Option) *DiffMatchPatch {
+	dmp := New()
+	for _, opt := range opts {
+		opt(dmp)
+	}
+	return dmp
+}
+
+// WithTimeout sets Diff_Timeout, 0 for no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(dmp *DiffMatchPatch) {
+		dmp.Diff_Timeout = d
+	}
+}
+
+// WithMatchThreshold sets Match_Threshold.
+func WithMatchThreshold(t float32) Option {
+	return func(dmp *DiffMatchPatch) {
+		dmp.Match_Threshold = t
+	}
+}
+
+// WithEditCost sets Diff_EditCost.
+func WithEditCost(c uint16) Option {
+	return func(dmp *DiffMatchPatch) {
+		dmp.Diff_EditCost = c
+	}
+}
+
+// The default diff entry method, sets checklines to true and continues
+func (dmp *DiffMatchPatch) DiffRecurse(inputA, inputB string) (error, []Diff) {
+	return nil, dmp.DiffString(inputA, inputB)
+}
+
+// Diff two strings with checklines set to true, returning only the diffs
+func (dmp *DiffMatchPatch) DiffString(inputA, inputB string) []Diff {
+	return dmp.DiffMainStrings(inputA, inputB, true)
+}
+
+// Diff two strings, returning only the diffs.  The texts are converted to
+// runes once, the whole diff then works on those.
+func (dmp *DiffMatchPatch) DiffMainStrings(inputA, inputB string, checklines bool) []Diff {
+	_, diffs := dmp.DiffMain([]rune(inputA), []rune(inputB), checklines)
+	return diffs
+}
+
+// DiffMainLimited is DiffString for untrusted input, refusing texts of more
+// than maxBytes bytes before any work is done (0 for no limit).  The bisect
+// allocates two int arrays the length of both texts together, 16 bytes per
+// rune on 64-bit platforms on top of the texts themselves, and takes time
+// proportional to the length times the number of edits, so large unrelated
+// texts cost a lot even with Diff_Timeout set.
+func (dmp *DiffMatchPatch) DiffMainLimited(inputA, inputB string, maxBytes int) ([]Diff, error) {
+	if maxBytes > 0 {
+		for _, input := range []string{inputA, inputB} {
+			if len(input) > maxBytes {
+				return nil, fmt.Errorf("input of %d bytes exceeds the limit of %d bytes", len(input), maxBytes)
+			}
+		}
+	}
+	return dmp.DiffString(inputA, inputB), nil
+}
+
+// Diff two rune slices with checklines set to true, returning only the diffs.
+// DiffMain only reports errors for broken internal invariants, so they are
+// not surfaced here.
+func (dmp *DiffMatchPatch) DiffMainRunes(inputA, inputB []rune) []Diff {
+	_, diffs := dmp.DiffMain(inputA, inputB, true)
+	return diffs
+}
+
+// Recursive diff method setting a deadline
+func (dmp *DiffMatchPatch) DiffMain(inputA, inputB []rune, checklines bool) (error, []Diff) {
+	diffs, err := dmp.DiffMainContext(context.Background(), inputA, inputB, checklines)
+	return err, diffs
+}
+
+// Diff method which can be cancelled through ctx. Diff_Timeout still yields
+// a coarse (but valid) diff when it expires; cancellation of ctx itself
+// returns ctx.Err().
+func (dmp *DiffMatchPatch) DiffMainContext(ctx context.Context, inputA, inputB []rune, checklines bool) ([]Diff, error) {
+	timeoutCtx, cancel := dmp.timeoutContext(ctx)
+	defer cancel()
+
+	diffs := dmp.diffMainContext(timeoutCtx, inputA, inputB, checklines)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// Diff like DiffMain, and report whether Diff_Timeout ran out.  The diff is
+// still valid then, but parts of it may be coarser than they could be.
+func (dmp *DiffMatchPatch) DiffMainTimed(inputA, inputB []rune, checklines bool) ([]Diff, bool) {
+	timedOut := new(atomic.Bool)
+	ctx, cancel := dmp.timeoutContext(context.WithValue(context.Background(), diffTimedOutKey{}, timedOut))
+	defer cancel()
+
+	diffs := dmp.diffMainContext(ctx, inputA, inputB, checklines)
+	return diffs, timedOut.Load()
+}
+
+// The context key of an *atomic.Bool which is set when the diff gave up on
+// a region because ctx was done.
+type diffTimedOutKey struct{}
+
+// Diff like DiffMain, and call progress now and then while a long region is
+// bisected: done edit steps of the bisect are explored, out of at most total.
+// Most bisects finish long before total, so the ratio is only a rough
+// lower bound of the progress, and each long region starts again from 0.
+// The calls come every Diff_CheckInterval steps, which keeps them cheap,
+// so bisects shorter than that don't report at all.  With Diff_Parallel
+// progress is still called by one goroutine at a time.
+func (dmp *DiffMatchPatch) DiffMainProgress(inputA, inputB []rune, checklines bool, progress func(done, total int)) []Diff {
+	ctx := context.Background()
+	if progress != nil {
+		ctx = context.WithValue(ctx, diffProgressKey{}, &diffProgress{report: progress})
+	}
+	ctx, cancel := dmp.timeoutContext(ctx)
+	defer cancel()
+
+	return dmp.diffMainContext(ctx, inputA, inputB, checklines)
+}
+
+// The context key of the *diffProgress of DiffMainProgress.
+type diffProgressKey struct{}
+
+type diffProgress struct {
+	mu     sync.Mutex
+	report func(done, total int)
+}
+
+func (p *diffProgress) update(done, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report(done, total)
+}
+
+// Derive a context from ctx which expires after Diff_Timeout, if set.
+func (dmp *DiffMatchPatch) timeoutContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if dmp.Diff_Timeout > 0 {
+		return context.WithTimeout(ctx, dmp.Diff_Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Diff method with deadline, a zero deadline means no deadline
+func (dmp *DiffMatchPatch) DiffMainDeadline(inputA, inputB []rune, checklines bool, deadline time.Time) (error, []Diff) {
+	ctx := context.Background()
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	return nil, dmp.diffMainContext(ctx, inputA, inputB, checklines)
+}
+
+// * diff_main
+// The work horse behind the DiffMain variants. Once ctx is done the remaining
+// regions are diffed coarsely.
+func (dmp *DiffMatchPatch) diffMainContext(ctx context.Context, inputA, inputB []rune, checklines bool) []Diff {
+	if dmp.Diff_MaxDiffs > 0 && ctx.Value(diffCountKey{}) == nil {
+		ctx = context.WithValue(ctx, diffCountKey{}, new(atomic.Int64))
+	}
+
+	// Check for equality (speedup).
+	if slices.Equal(inputA, inputB) {
+		var diffs []Diff
+		if len(inputA) > 0 {
+			diffs = append(diffs, Diff{EQUAL, string(inputA)})
+		}
+		return streamDiffs(ctx, diffs)
+	}
+
+	// Trim off common prefix (speedup).
+	commonLength := dmp.DiffCommonPrefix(inputA, inputB)
+	commonPrefix := inputA[:commonLength]
+	textChoppedA := inputA[commonLength:]
+	textChoppedB := inputB[commonLength:]
+
+	// Trim off common suffix (speedup).
+	commonLength = dmp.DiffCommonSuffix(textChoppedA, textChoppedB)
+	commonSuffix := textChoppedA[len(textChoppedA)-commonLength:]
+	textChoppedA = textChoppedA[:len(textChoppedA)-commonLength]
+	textChoppedB = textChoppedB[:len(textChoppedB)-commonLength]
+
+	// Compute the diff on the middle block, between the prefix and suffix.
+	var diffs []Diff
+	if len(commonPrefix) > 0 {
+		diffs = streamDiffs(ctx, []Diff{{EQUAL, string(commonPrefix)}})
+		countDiffs(ctx, 1)
+	}
+	diffs = append(diffs, dmp.DiffCompute(ctx, textChoppedA, textChoppedB, checklines)...)
+	if len(commonSuffix) > 0 {
+		diffs = append(diffs, Diff{EQUAL, string(commonSuffix)})
+		countDiffs(ctx, 1)
+	}
+	if streaming(ctx) {
+		// The merger of DiffMainCallback merges as the diffs come.
+		return streamDiffs(ctx, diffs)
+	}
+	_, diffs = dmp.DiffCleanupMerge(diffs)
+
+	return diffs
+}
+
+// The context key of the number of diffs found so far, an *atomic.Int64
+// which is only set when Diff_MaxDiffs applies.
+type diffCountKey struct{}
+
+// Add n diffs to the count in ctx.
+func countDiffs(ctx context.Context, n int) {
+	if count, ok := ctx.Value(diffCountKey{}).(*atomic.Int64); ok {
+		count.Add(int64(n))
+	}
+}
+
+// Whether the diff in ctx has reached Diff_MaxDiffs.
+func (dmp *DiffMatchPatch) maxDiffsReached(ctx context.Context) bool {
+	count, ok := ctx.Value(diffCountKey{}).(*atomic.Int64)
+	return ok && count.Load() >= int64(dmp.Diff_MaxDiffs)
+}
+
+// * diffCompute_
+func (dmp *DiffMatchPatch) DiffCompute(ctx context.Context, textA, textB []rune, checklines bool) []Diff {
+	diffs := []Diff{}
+
+	if len(textA) == 0 {
+		// Just add some text (speedup).
+		diffs = append(diffs, Diff{INSERT, string(textB)})
+		countDiffs(ctx, len(diffs))
+		return diffs
+	}
+
+	if len(textB) == 0 {
+		// Just delete some text (speedup).
+		diffs = append(diffs, Diff{DELETE, string(textA)})
+		countDiffs(ctx, len(diffs))
+		return diffs
+	}
+
+	var longtext, shorttext []rune
+	if len(textA) > len(textB) {
+		longtext = textA
+		shorttext = textB
+	} else {
+		longtext = textB
+		shorttext = textA
+	}
+	// A rune index, longtext is sliced by it.
+	foundTextIndex := runesIndexOf(longtext, shorttext, 0)
+	if foundTextIndex != -1 {
+		// Shorter text is inside the longer text (speedup).
+		var op Operation
+
+		if len(textA) > len(textB) {
+			op = DELETE
+		} else {
+			op = INSERT
+		}
+		diffs = append(diffs, Diff{op, string(longtext[:foundTextIndex])})
+		diffs = append(diffs, Diff{EQUAL, string(shorttext)})
+		diffs = append(diffs, Diff{op, string(longtext[foundTextIndex+len(shorttext):])})
+		countDiffs(ctx, len(diffs))
+		return diffs
+	}
+
+	if len(shorttext) == 1 || dmp.maxDiffsReached(ctx) {
+		// Single character string.
+		// After the previous speedup, the character can't be an equality.
+		// Past Diff_MaxDiffs the rest is not refined any further either.
+		diffs = append(diffs, Diff{DELETE, string(textA)})
+		diffs = append(diffs, Diff{INSERT, string(textB)})
+		countDiffs(ctx, len(diffs))
+		return diffs
+	}
+
+	// Check to see if the problem can be split in two.
+	textA_1, textA_2, textB_1, textB_2, midCommon := dmp.DiffHalfMatch(textA, textB)
+
+	if len(textA_1) > 0 {
+		// A half-match was found.
+		// Send both pairs off for separate processing.
+		// A streamed diff needs the common middle before the second pair.
+		diffs_a := dmp.diffMainContext(ctx, textA_1, textB_1, checklines)
+		diffs = streamDiffs(ctx, append(diffs_a, Diff{EQUAL, string(midCommon)}))
+		diffs_b := dmp.diffMainContext(ctx, textA_2, textB_2, checklines)
+
+		// Merge the results.
+		diffs = append(diffs, diffs_b...)
+		countDiffs(ctx, 1)
+		return diffs
+	}
+
+	// Perform a real diff.
+	if checklines && len(textA) > 100 && len(textB) > 100 {
+		return dmp.DiffLineMode(ctx, textA, textB)
+	}
+
+	return dmp.diffBisect(ctx, textA, textB)
+}
+
+// * diffLineMode_
+func (dmp *DiffMatchPatch) DiffLineMode(ctx context.Context, textA, textB []rune) []Diff {
+	// The line mode rediffs its own result, so a streamed diff gets it whole.
+	ctx = context.WithValue(ctx, diffStreamKey{}, (*diffStreamMerger)(nil))
+
+	// Scan the text on a line-by-line basis first.
+	textA, textB, lineArray := dmp.DiffLinesToRunes(string(textA), string(textB))
+
+	diffs := dmp.diffMainContext(ctx, textA, textB, false)
+
+	// Convert the diff back to original text.
+	diffs = dmp.DiffCharsToLines(diffs, lineArray)
+	// Eliminate freak matches (e.g. blank lines)
+	diffs = dmp.DiffCleanupSemantic(diffs)
+
+	// Rediff any replacement blocks, this time character-by-character.
+	// Add a dummy entry at the end.
+	diffs = append(diffs, Diff{EQUAL, ""})
+	count_delete, count_insert := 0, 0
+	text_delete := ""
+	text_insert := ""
+
+	for pointer := 0; pointer < len(diffs); pointer++ {
+		switch diffs[pointer].Type {
+		case INSERT:
+			count_insert++
+			text_insert += diffs[pointer].Text
+		case DELETE:
+			count_delete++
+			text_delete += diffs[pointer].Text
+		case EQUAL:
+			// Upon reaching an equality, check for prior redundancies.
+			if count_delete >= 1 && count_insert >= 1 {
+				// Replace the offending records with their character diff, in
+				// place, and carry on after it.  The edits were all counted
+				// since the last equality, so start is within diffs.
+				start := pointer - count_delete - count_insert
+				newDiffs := dmp.diffMainContext(ctx, []rune(text_delete), []rune(text_insert), false)
+				diffs = slices.Replace(diffs, start, pointer, newDiffs...)
+				pointer = start + len(newDiffs)
+			}
+			count_insert = 0
+			count_delete = 0
+			text_delete = ""
+			text_insert = ""
+		}
+	}
+	diffs = diffs[:len(diffs)-1] // Remove the dummy entry at the end.
+	return diffs
+}
+
+// Diff two texts word by word, so no edit ever starts or ends inside a
+// word.  Prose reads much better this way than as a rune level diff.
+func (dmp *DiffMatchPatch) DiffWordMode(textA, textB string) []Diff {
+	ctx, cancel := dmp.timeoutContext(context.Background())
+	defer cancel()
+
+	// Scan the text on a word-by-word basis.
+	wordsA, wordsB, wordArray := dmp.DiffWordsToChars(textA, textB)
+
+	diffs := dmp.diffMainContext(ctx, wordsA, wordsB, false)
+
+	// Convert the diff back to original text.
+	return dmp.DiffCharsToLines(diffs, wordArray)
+}
+
+// A TokenFunc splits a text into the tokens to diff, in order.  The tokens
+// must join up to the text again.
+type TokenFunc func(text string) []string
+
+// Diff two texts token by token, with tokens such as sentences or
+// statements from split.  This is DiffLineMode and DiffWordMode for any
+// granularity, edits never start or end inside a token.
+func (dmp *DiffMatchPatch) DiffTokenMode(textA, textB string, split TokenFunc) []Diff {
+	ctx, cancel := dmp.timeoutContext(context.Background())
+	defer cancel()
+
+	tokenArray := []string{""} // Index 0 is reserved, as in DiffLinesToStrings.
+	tokenHash := make(map[string]int)
+	tokensA := intArrayToString(diffTokensMunge(split(textA), &tokenArray, tokenHash))
+	tokensB := intArrayToString(diffTokensMunge(split(textB), &tokenArray, tokenHash))
+
+	diffs := dmp.diffMainContext(ctx, []rune(tokensA), []rune(tokensB), false)
+
+	// Convert the diff back to original text.
+	return dmp.DiffCharsToLines(diffs, tokenArray)
+}
+
+// Record each unseen token in tokenArray and tokenHash, and return the
+// index of every token, skipping empty ones.  Once tokenArray is full the
+// remaining tokens are joined into one.
+func diffTokensMunge(tokens []string, tokenArray *[]string, tokenHash map[string]int) []uint32 {
+	strs := make([]uint32, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if lineArrayFull(*tokenArray) {
+			token = strings.Join(tokens[i:], "")
+			i = len(tokens)
+		}
+		if len(token) == 0 {
+			continue
+		}
+		tokenValue, ok := tokenHash[token]
+		if !ok {
+			*tokenArray = append(*tokenArray, token)
+			tokenValue = len(*tokenArray) - 1
+			tokenHash[token] = tokenValue
+		}
+		strs = append(strs, uint32(tokenValue))
+	}
+	return strs
+}
+
+// How many iterations of the bisect loop to run between checks whether the
+// diff was cancelled or timed out, unless Diff_CheckInterval says otherwise.
+const defaultBisectCheckInterval = 256
+
+func (dmp *DiffMatchPatch) bisectCheckInterval() int {
+	if dmp.Diff_CheckInterval > 0 {
+		return dmp.Diff_CheckInterval
+	}
+	return defaultBisectCheckInterval
+}
+
+// The v1 and v2 arrays of the bisect, kept in a pool so that diffing many
+// small texts doesn't allocate them over and over.  Arrays for long texts
+// are dropped rather than pinned in the pool.
+type bisectScratch struct {
+	v []int
+}
+
+const maxPooledBisectScratch = 1 << 16
+
+var bisectScratchPool = sync.Pool{
+	New: func() any { return new(bisectScratch) },
+}
+
+func getBisectScratch(n int) *bisectScratch {
+	scratch := bisectScratchPool.Get().(*bisectScratch)
+	if cap(scratch.v) < n {
+		scratch.v = make([]int, n)
+	}
+	scratch.v = scratch.v[:n]
+	return scratch
+}
+
+func putBisectScratch(scratch *bisectScratch) {
+	if cap(scratch.v) <= maxPooledBisectScratch {
+		bisectScratchPool.Put(scratch)
+	}
+}
+
+// * diffBisect_
+func (dmp *DiffMatchPatch) DiffBisect_(ctx context.Context, textA, textB string) []Diff {
+	return dmp.diffBisect(ctx, []rune(textA), []rune(textB))
+}
+
+// Find the middle snake of textA and textB and diff both halves.
+func (dmp *DiffMatchPatch) diffBisect(ctx context.Context, textA, textB []rune) []Diff {
+	var x, y int
+	var found bool
+	if isASCII(textA) && isASCII(textB) {
+		// Bytes and runes are the same thing, compare the smaller bytes.
+		x, y, found = diffBisectMiddleSnake(ctx, asciiBytes(textA), asciiBytes(textB), dmp.bisectCheckInterval())
+	} else {
+		x, y, found = diffBisectMiddleSnake(ctx, textA, textB, dmp.bisectCheckInterval())
+	}
+	if found {
+		return dmp.DiffBisectSplit(ctx, textA, textB, x, y)
+	}
+
+	// Diff took too long and hit the deadline or
+	// number of diffs equals number of characters, no commonality at all.
+	if ctx.Err() != nil {
+		if timedOut, ok := ctx.Value(diffTimedOutKey{}).(*atomic.Bool); ok {
+			timedOut.Store(true)
+		}
+	}
+	var diffs []Diff
+	if len(textA) > 0 {
+		diffs = append(diffs, Diff{DELETE, string(textA)})
+	}
+	if len(textB) > 0 {
+		diffs = append(diffs, Diff{INSERT, string(textB)})
+	}
+	countDiffs(ctx, len(diffs))
+	return diffs
+}
+
+func isASCII(runes []rune) bool {
+	for _, r := range runes {
+		if r >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Narrow ASCII runes to bytes.
+func asciiBytes(runes []rune) []byte {
+	text := make([]byte, len(runes))
+	for i, r := range runes {
+		text[i] = byte(r)
+	}
+	return text
+}
+
+// Find the 'middle snake' of a diff, the point at which to split it in two.
+// Returns false if the texts have nothing in common or ctx expired first.
+// Indices count elements of textA and textB: runes, bytes of ASCII text, or
+// the tokens of DiffSlices.
+// Memory is linear, two arrays of about len(textA)+len(textB) ints, but the
+// time grows with the length times the number of edits, quadratic for
+// texts with nothing in common.
+func diffBisectMiddleSnake[E comparable](ctx context.Context, textA, textB []E, checkInterval int) (int, int, bool) {
+	textALen := len(textA)
+	textBLen := len(textB)
+	var max_d int = (textALen + textBLen + 1) / 2
+	v_offset := max_d
+	// Two more than the paths reach, so v_offset+1 is in range even when
+	// the texts are too short to be worth bisecting.
+	v_length := 2*max_d + 2
+	scratch := getBisectScratch(2 * v_length)
+	defer putBisectScratch(scratch)
+	v1 := scratch.v[:v_length]
+	v2 := scratch.v[v_length : 2*v_length]
+
+	for x := range v1 {
+		v1[x] = -1
+		v2[x] = -1
+	}
+	v1[v_offset+1] = 0
+	v2[v_offset+1] = 0
+
+	delta := textALen - textBLen
+	progress, _ := ctx.Value(diffProgressKey{}).(*diffProgress)
+
+	// If the total number of characters is odd, then the front path will
+	// collide with the reverse path.
+	front := (delta%2 != 0)
+
+	// Offsets for start and end of k loop.
+	// Prevents mapping of space beyond the grid.
+	k1start := 0
+	k1end := 0
+	k2start := 0
+	k2end := 0
+
+	for d := 0; d < max_d; d++ {
+		// Bail out if the deadline is reached or the diff was cancelled.
+		if d%checkInterval == 0 {
+			if ctx.Err() != nil {
+				break
+			}
+			if d > 0 && progress != nil {
+				progress.update(d, max_d)
+			}
+		}
+		// Walk the front path one step.
+		for k1 := -d + k1start; k1 <= d-k1end; k1 += 2 {
+			k1_offset := v_offset + k1
+			var x1 int
+			if k1 == -d || (k1 != d && v1[k1_offset-1] < v1[k1_offset+1]) {
+				x1 = v1[k1_offset+1]
+			} else {
+				x1 = v1[k1_offset-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < textALen && y1 < textBLen && textA[x1] == textB[y1] {
+				x1++
+				y1++
+			}
+			v1[k1_offset] = x1
+			if x1 > textALen {
+				// Ran off the right of the graph.
+				k1end += 2
+			} else if y1 > textBLen {
+				// Ran off the bottom of the graph.
+				k1start += 2
+			} else if front {
+				k2_offset := v_offset + delta - k1
+				if k2_offset >= 0 && k2_offset < v_length && v2[k2_offset] != -1 {
+					// Mirror x2 onto top-left coordinate system.
+					x2 := textALen - v2[k2_offset]
+					if x1 >= x2 {
+						// Overlap detected.
+						return x1, y1, true
+					}
+				}
+			}
+		}
+
+		// Walk the reverse path one step.
+		for k2 := -d + k2start; k2 <= d-k2end; k2 += 2 {
+			k2_offset := v_offset + k2
+			var x2 int
+			if k2 == -d || (k2 != d && v2[k2_offset-1] < v2[k2_offset+1]) {
+				x2 = v2[k2_offset+1]
+			} else {
+				x2 = v2[k2_offset-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < textALen && y2 < textBLen && textA[textALen-x2-1] == textB[textBLen-y2-1] {
+				x2++
+				y2++
+			}
+			v2[k2_offset] = x2
+			if x2 > textALen {
+				// Ran off the left of the graph.
+				k2end += 2
+			} else if y2 > textBLen {
+				// Ran off the top of the graph.
+				k2start += 2
+			} else if !front {
+				k1_offset := v_offset + delta - k2
+				if k1_offset >= 0 && k1_offset < v_length && v1[k1_offset] != -1 {
+					x1 := v1[k1_offset]
+					y1 := v_offset + x1 - k1_offset
+					// Mirror x2 onto top-left coordinate system.
+					x2 = textALen - x2
+					if x1 >= x2 {
+						// Overlap detected.
+						return x1, y1, true
+					}
+				}
+			}
+		}
+
+	}
+
+	return 0, 0, false
+}
+
+// * diffBisectSplit
+func (dmp *DiffMatchPatch) DiffBisectSplit(ctx context.Context, textA, textB []rune, x, y int) []Diff {
+	textA1 := textA[:x]
+	textB1 := textB[:y]
+	textA2 := textA[x:]
+	textB2 := textB[y:]
+
+	if dmp.Diff_Parallel && len(textA)+len(textB) >= parallelSplitMinRunes && !streaming(ctx) {
+		// The halves are independent, diff the second one in its own goroutine.
+		// Both share ctx, so the deadline still applies.
+		var diffsb []Diff
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			diffsb = dmp.diffMainContext(ctx, textA2, textB2, false)
+		}()
+		diffs := dmp.diffMainContext(ctx, textA1, textB1, false)
+		<-done
+		return append(diffs, diffsb...)
+	}
+
+	// Compute both diffs serially.
+	diffs := dmp.diffMainContext(ctx, textA1, textB1, false)
+	diffsb := dmp.diffMainContext(ctx, textA2, textB2, false)
+
+	return append(diffs, diffsb...)
+}
+
+// Bisections of fewer runes than this are split serially even with
+// Diff_Parallel, a goroutine costs more than diffing them.
+const parallelSplitMinRunes = 1 << 14
+
+// * diffLinesToChars
+func (dmp *DiffMatchPatch) DiffLinesToChars(textA, textB string) ([]rune, []rune, []string) {
+
+	// "\x00" is a valid character, but various debuggers don't like it.
+	// So we'll insert a junk entry to avoid generating a null character.
+	lineArray := []string{""}
+	lineHash := make(map[string]int)
+	// e.g. linearray[4] == "Hello\n"
+	// e.g. linehash.get("Hello\n") == 4
+
+	chars1 := dmp.DiffLinesToCharsMunge(textA, &lineArray, lineHash)
+	chars2 := dmp.DiffLinesToCharsMunge(textB, &lineArray, lineHash)
+
+	return chars1, chars2, lineArray
+}
+
+// These constants define the number of bits representable
+// in 1,2,3,4 byte utf8 sequences, respectively.
+const ONE_BYTE_BITS = 7
+const TWO_BYTE_BITS = 11
+const THREE_BYTE_BITS = 16
+const FOUR_BYTE_BITS = 21
+
+const UNICODE_INVALID_RANGE_START = 0xD800
+const UNICODE_INVALID_RANGE_END = 0xDFFF
+const UNICODE_INVALID_RANGE_DELTA = UNICODE_INVALID_RANGE_END - UNICODE_INVALID_RANGE_START + 1
+const UNICODE_RANGE_MAX = 0x10FFFF
+
+// MAX_RUNE_INDEX is the largest index intToRune can encode, skipping the
+// surrogate range and U+FFFD-U+FFFF.  Index 0 is reserved, so a diff can tell
+// apart at most MAX_RUNE_INDEX unique lines (or words).  Past that the munge
+// functions stop splitting and encode the rest of the text as one last line,
+// which keeps the diff correct, only coarser.
+const MAX_RUNE_INDEX = UNICODE_RANGE_MAX - UNICODE_INVALID_RANGE_DELTA - 3
+
+func (dmp *DiffMatchPatch) DiffLinesToRunes(text1, text2 string) ([]rune, []rune, []string) {
+	chars1, chars2, lineArray := dmp.DiffLinesToStrings(text1, text2)
+	return []rune(chars1), []rune(chars2), lineArray
+}
+
+func (dmp *DiffMatchPatch) DiffLinesToStrings(text1, text2 string) (string, string, []string) {
+	// '\x00' is a valid character, but various debuggers don't like it. So we'll insert a junk entry to avoid generating a null character.
+	lineArray := []string{""} // e.g. lineArray[4] == 'Hello\n'
+
+	lineHash := getLineHash()
+	defer putLineHash(lineHash)
+	//Each string has the index of lineArray which it points to
+	strIndexArray1 := dmp.DiffLinesToStringsMunge(text1, &lineArray, lineHash)
+	strIndexArray2 := dmp.DiffLinesToStringsMunge(text2, &lineArray, lineHash)
+
+	return intArrayToString(strIndexArray1), intArrayToString(strIndexArray2), lineArray
+}
+
+// Split two texts into a list of words.  Reduce the texts to a string of
+// runes where each rune represents one word (a run of word characters) or
+// one run of separators between words.
+func (dmp *DiffMatchPatch) DiffWordsToChars(text1, text2 string) ([]rune, []rune, []string) {
+	chars1, chars2, wordArray := dmp.DiffWordsToStrings(text1, text2)
+	return []rune(chars1), []rune(chars2), wordArray
+}
+
+// DiffWordsToStrings is DiffWordsToChars returning the encoded texts as
+// strings, like DiffLinesToStrings.
+func (dmp *DiffMatchPatch) DiffWordsToStrings(text1, text2 string) (string, string, []string) {
+	// '\x00' is a valid character, but various debuggers don't like it. So we'll insert a junk entry to avoid generating a null character.
+	wordArray := []string{""} // e.g. wordArray[4] == 'Hello'
+
+	wordHash := make(map[string]int)
+	strIndexArray1 := dmp.DiffWordsToCharsMunge(text1, &wordArray, wordHash)
+	strIndexArray2 := dmp.DiffWordsToCharsMunge(text2, &wordArray, wordHash)
+
+	return intArrayToString(strIndexArray1), intArrayToString(strIndexArray2), wordArray
+}
+
+// Split a text into words, recording each unseen word in wordArray and
+// wordHash, and return the index of every word of the text.
+func (dmp *DiffMatchPatch) DiffWordsToCharsMunge(text string, wordArray *[]string, wordHash map[string]int) []uint32 {
+	strs := []uint32{}
+
+	wordStart := 0
+	for wordStart < len(text) {
+		r, size := utf8.DecodeRuneInString(text[wordStart:])
+		inWord := isWordRune(r)
+		wordEnd := wordStart + size
+		if lineArrayFull(*wordArray) {
+			wordEnd = len(text)
+		}
+		for wordEnd < len(text) {
+			r, size = utf8.DecodeRuneInString(text[wordEnd:])
+			if isWordRune(r) != inWord {
+				break
+			}
+			wordEnd += size
+		}
+
+		word := text[wordStart:wordEnd]
+		wordStart = wordEnd
+		wordValue, ok := wordHash[word]
+
+		if ok {
+			strs = append(strs, uint32(wordValue))
+		} else {
+			*wordArray = append(*wordArray, word)
+			wordHash[word] = len(*wordArray) - 1
+			strs = append(strs, uint32(len(*wordArray)-1))
+		}
+	}
+
+	return strs
+}
+
+// Word runes are letters, digits and combining marks in any script.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+func intArrayToString(ns []uint32) string {
+	if len(ns) == 0 {
+		return ""
+	}
+
+	b := []rune{}
+	for _, n := range ns {
+		b = append(b, intToRune(n))
+	}
+	return string(b)
+}
+
+func getBits(i uint32, cnt byte, from byte) byte {
+	return byte((i >> from) & ((1 << cnt) - 1))
+}
+
+func indexOf(s string, needle string, startIndex int) int {
+	if startIndex > len(s)-1 {
+		return -1
+	}
+	if startIndex <= 0 {
+		return strings.Index(s, needle)
+	}
+	index := strings.Index(s[startIndex:], needle)
+	if index == -1 {
+		return -1
+	}
+	return index + startIndex
+}
+
+func intToRune(i uint32) rune {
+	if i < (1 << ONE_BYTE_BITS) {
+		return rune(i)
+	}
+
+	if i < (1 << TWO_BYTE_BITS) {
+		r, size := utf8.DecodeRune([]byte{0b11000000 | getBits(i, 5, 6), 0b10000000 | getBits(i, 6, 0)})
+		if size != 2 || r == utf8.RuneError {
+			panic(fmt.Sprintf("Error encoding an int %d with size 2, got rune %v and size %d", i, r, size))
+		}
+		return r
+	}
+
+	// Last -3 here needed because for some reason 3rd to last codepoint 65533 in this range
+	// was returning utf8.RuneError during encoding.
+	if i < ((1 << THREE_BYTE_BITS) - UNICODE_INVALID_RANGE_DELTA - 3) {
+		if i >= UNICODE_INVALID_RANGE_START {
+			i += UNICODE_INVALID_RANGE_DELTA
+		}
+
+		r, size := utf8.DecodeRune([]byte{0b11100000 | getBits(i, 4, 12), 0b10000000 | getBits(i, 6, 6), 0b10000000 | getBits(i, 6, 0)})
+		if size != 3 || r == utf8.RuneError {
+			panic(fmt.Sprintf("Error encoding an int %d with size 3, got rune %v and size %d", i, r, size))
+		}
+		return r
+	}
+
+	if i <= MAX_RUNE_INDEX {
+		i += UNICODE_INVALID_RANGE_DELTA + 3
+		r, size := utf8.DecodeRune([]byte{0b11110000 | getBits(i, 3, 18), 0b10000000 | getBits(i, 6, 12), 0b10000000 | getBits(i, 6, 6), 0b10000000 | getBits(i, 6, 0)})
+		if size != 4 || r == utf8.RuneError {
+			panic(fmt.Sprintf("Error encoding an int %d with size 4, got rune %v and size %d", i, r, size))
+		}
+		return r
+	}
+	panic(fmt.Sprintf("The integer %d is too large for intToRune()", i))
+}
+
+// runeToInt is the inverse of intToRune.
+func runeToInt(r rune) uint32 {
+	if r < UNICODE_INVALID_RANGE_START {
+		return uint32(r)
+	}
+	if r < 1<<THREE_BYTE_BITS {
+		return uint32(r) - UNICODE_INVALID_RANGE_DELTA
+	}
+	return uint32(r) - UNICODE_INVALID_RANGE_DELTA - 3
+}
+
+// lineArrayFull reports whether the munge functions must stop splitting
+// text.  The last index is kept free so that the second text always has room
+// for its remainder.
+func lineArrayFull(lineArray []string) bool {
+	return len(lineArray) >= MAX_RUNE_INDEX-1
+}
+
+func (dmp *DiffMatchPatch) DiffLinesToStringsMunge(text string, lineArray *[]string, lineHash map[string]int) []uint32 {
+	return diffLinesMunge(text, lineArray, nil, lineHash)
+}
+
+// The line hash of DiffLinesToStrings is only needed while the texts are
+// split, so it goes back to a pool afterwards.  Emptying a map keeps its
+// buckets, which saves growing it again for the next diff.  Maps of more
+// lines than that are dropped.
+const maxPooledLineHash = 1 << 12
+
+var lineHashPool = sync.Pool{
+	New: func() any { return make(map[string]int) },
+}
+
+func getLineHash() map[string]int {
+	return lineHashPool.Get().(map[string]int)
+}
+
+func putLineHash(lineHash map[string]int) {
+	if len(lineHash) <= maxPooledLineHash {
+		clear(lineHash)
+		lineHashPool.Put(lineHash)
+	}
+}
+
+// Split text into lines like DiffLinesToStringsMunge, looking lines up in the
+// read-only baseHash before lineHash.  New lines only go into lineHash.
+func diffLinesMunge(text string, lineArray *[]string, baseHash, lineHash map[string]int) []uint32 {
+	// Walk the text, pulling out a substring for each line. text.split('\n') would would temporarily double our memory footprint. Modifying text would create many large strings to garbage collect.
+	lineStart := 0
+	lineEnd := -1
+	strs := []uint32{}
+
+	for lineEnd < len(text)-1 {
+		lineEnd = indexOf(text, "\n", lineStart)
+
+		if lineEnd == -1 || lineArrayFull(*lineArray) {
+			lineEnd = len(text) - 1
+		}
+
+		line := text[lineStart : lineEnd+1]
+		lineStart = lineEnd + 1
+		lineValue, ok := baseHash[line]
+		if !ok {
+			lineValue, ok = lineHash[line]
+		}
+
+		if ok {
+			strs = append(strs, uint32(lineValue))
+		} else {
+			*lineArray = append(*lineArray, line)
+			lineHash[line] = len(*lineArray) - 1
+			strs = append(strs, uint32(len(*lineArray)-1))
+		}
+	}
+
+	return strs
+}
+
+// * diffLinestoCharsMunge
+// A LineEncoder encodes texts line by line against a fixed base text, like
+// DiffLinesToStrings(base, other), but splits and hashes the base only once.
+// Encode may be called from several goroutines.
+type LineEncoder struct {
+	baseChars string
+	lineArray []string
+	lineHash  map[string]int
+}
+
+// NewLineEncoder returns a LineEncoder for the base text.
+func NewLineEncoder(base string) *LineEncoder {
+	// '\x00' is a valid character, but various debuggers don't like it. So we'll insert a junk entry to avoid generating a null character.
+	lineArray := []string{""}
+	lineHash := make(map[string]int)
+	chars := diffLinesMunge(base, &lineArray, nil, lineHash)
+	return &LineEncoder{intArrayToString(chars), lineArray, lineHash}
+}
+
+// Encode returns the same as DiffLinesToStrings(base, other): both texts with
+// each line replaced by one rune, and the lines to turn the diff of those
+// back into text with DiffCharsToLines.
+func (e *LineEncoder) Encode(other string) (baseChars, otherChars string, lines []string) {
+	// Lines only found in other must not leak into later calls.
+	lines = make([]string, len(e.lineArray))
+	copy(lines, e.lineArray)
+	chars := diffLinesMunge(other, &lines, e.lineHash, make(map[string]int))
+	return e.baseChars, intArrayToString(chars), lines
+}
+
+func (dmp *DiffMatchPatch) DiffLinesToCharsMunge(text string, lineArray *[]string, lineHash map[string]int) []rune {
+	lineStart := 0
+	lineEnd := -1
+	chars := []rune{}
+
+	// Walk the text, pulling out a substring for each line.
+	// text.split('\n') would would temporarily double our memory footprint.
+	// Modifying text would create many large strings to garbage collect.
+	for lineEnd < len(text)-1 {
+		lineEnd = indexOf(text, "\n", lineStart)
+		if lineEnd == -1 || lineArrayFull(*lineArray) {
+			lineEnd = len(text) - 1
+		}
+		line := text[lineStart : lineEnd+1]
+		lineStart = lineEnd + 1
+		lineValue, ok := lineHash[line]
+
+		if ok {
+			chars = append(chars, intToRune(uint32(lineValue)))
+		} else {
+			*lineArray = append(*lineArray, line)
+			lineHash[line] = len(*lineArray) - 1
+			chars = append(chars, intToRune(uint32(len(*lineArray)-1)))
+		}
+	}
+	return chars
+}
+
+// * diffCharsToLines
+func (dmp *DiffMatchPatch) DiffCharsToLines(diffs []Diff, lineArray []string) []Diff {
+	diffsWithText := make([]Diff, 0, len(diffs))
+
+	for _, diff := range diffs {
+		text := make([]string, 0, len(diff.Text))
+
+		for _, r := range diff.Text {
+			text = append(text, lineArray[runeToInt(r)])
+		}
+		diff.Text = strings.Join(text, "")
+		diffsWithText = append(diffsWithText, diff)
+	}
+
+	return diffsWithText
+}
+
+// * diffCommonPrefix
+func (dmp *DiffMatchPatch) DiffCommonPrefix(textA, textB []rune) int {
+	return commonPrefixLength(textA, textB)
+}
+
+// The length of the common prefix of textA and textB, in elements.
+func commonPrefixLength[E comparable](textA, textB []E) int {
+	// Performance analysis: http://neil.fraser.name/news/2007/10/09/
+	n := min(len(textA), len(textB))
+	for i := 0; i < n; i++ {
+		if textA[i] != textB[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// * diffCommonSuffix
+func (dmp *DiffMatchPatch) DiffCommonSuffix(textA, textB []rune) int {
+	return commonSuffixLength(textA, textB)
+}
+
+// The length of the common suffix of textA and textB, in elements.
+func commonSuffixLength[E comparable](textA, textB []E) int {
+	// Performance analysis: http://neil.fraser.name/news/2007/10/09/
+	textALen := len(textA)
+	textBLen := len(textB)
+	n := min(textALen, textBLen)
+	for i := 0; i < n; i++ {
+		if textA[textALen-1-i] != textB[textBLen-1-i] {
+			return i
+		}
+	}
+	return n
+}
+
+func (dmp *DiffMatchPatch) DiffCommonPrefixString(textA, textB string) int {
+	return dmp.DiffCommonPrefix([]rune(textA), []rune(textB))
+}
+
+func (dmp *DiffMatchPatch) DiffCommonSuffixString(textA, textB string) int {
+	return dmp.DiffCommonSuffix([]rune(textA), []rune(textB))
+}
+
+// * diffCommonOverlap
+// The number of runes at the end of textA which are also the start of
+// textB.
+func (dmp *DiffMatchPatch) DiffCommonOverlap(textA, textB string) int {
+	return dmp.DiffCommonOverlapRunes([]rune(textA), []rune(textB))
+}
+
+// DiffCommonOverlapRunes is DiffCommonOverlap on runes.
+func (dmp *DiffMatchPatch) DiffCommonOverlapRunes(textA, textB []rune) int {
+	// Cache the text lengths to prevent multiple calls.
+	textALen := len(textA)
+	textBLen := len(textB)
+	// Eliminate the null case.
+	if textALen == 0 || textBLen == 0 {
+		return 0
+	}
+	// Truncate the longer string.
+	textA_trunc := textA
+	textB_trunc := textB
+	if textALen > textBLen {
+		textA_trunc = textA[textALen-textBLen:]
+	} else if textALen < textBLen {
+		textB_trunc = textB[:textALen]
+	}
+	text_length := min(textALen, textBLen)
+	// Quick check for the worst case.
+	if slices.Equal(textA_trunc, textB_trunc) {
+		return text_length
+	}
+
+	// Start by looking for a single character match
+	// and increase length until no match is found.
+	// Performance analysis: http://neil.fraser.name/news/2010/11/04/
+	best := 0
+	length := 1
+	for {
+		pattern := textA_trunc[len(textA_trunc)-length:]
+		found := runesIndexOf(textB_trunc, pattern, 0)
+		if found == -1 {
+			return best
+		}
+		length += found
+		if found == 0 || slices.Equal(textA_trunc[len(textA_trunc)-length:], textB_trunc[:length]) {
+			best = length
+			length++
+		}
+	}
+}
+
+// * diffHalfMatch
+func (dmp *DiffMatchPatch) DiffHalfMatch(textA, textB []rune) ([]rune, []rune, []rune, []rune, []rune) {
+	if dmp.Diff_Timeout <= 0 {
+		// Don't risk returning a non-optimal diff if we have unlimited time.
+		return nil, nil, nil, nil, nil
+	}
+
+	var longtext, shorttext []rune
+	if len(textA) > len(textB) {
+		longtext = textA
+		shorttext = textB
+	} else {
+		longtext = textB
+		shorttext = textA
+	}
+
+	if len(longtext) < 4 || len(shorttext)*2 < len(longtext) {
+		return nil, nil, nil, nil, nil // Pointless.
+	}
+
+	var hm_best_longtext_a, hm_best_longtext_b, hm_best_shorttext_a, hm_best_shorttext_b, hm_best_common []rune
+	// First check if the second quarter is the seed for a half-match.
+	hm1_best_longtext_a, hm1_best_longtext_b, hm1_best_shorttext_a, hm1_best_shorttext_b, hm1_best_common := dmp.DiffHalfMatchI(longtext, shorttext,
+		(len(longtext)+3)/4)
+	// Check again based on the third quarter.
+	hm2_best_longtext_a, hm2_best_longtext_b, hm2_best_shorttext_a, hm2_best_shorttext_b, hm2_best_common := dmp.DiffHalfMatchI(longtext, shorttext,
+		(len(longtext)+1)/2)
+	if len(hm1_best_common) == 0 && len(hm2_best_common) == 0 {
+		return nil, nil, nil, nil, nil
+	} else if len(hm2_best_common) == 0 {
+		hm_best_longtext_a, hm_best_longtext_b, hm_best_shorttext_a, hm_best_shorttext_b, hm_best_common = hm1_best_longtext_a, hm1_best_longtext_b, hm1_best_shorttext_a, hm1_best_shorttext_b, hm1_best_common
+	} else if len(hm1_best_common) == 0 {
+		hm_best_longtext_a, hm_best_longtext_b, hm_best_shorttext_a, hm_best_shorttext_b, hm_best_common = hm2_best_longtext_a, hm2_best_longtext_b, hm2_best_shorttext_a, hm2_best_shorttext_b, hm2_best_common
+	} else {
+		// Both matched.  Select the longest.
+		if len(hm1_best_common) > len(hm2_best_common) {
+			hm_best_longtext_a, hm_best_longtext_b, hm_best_shorttext_a, hm_best_shorttext_b, hm_best_common = hm1_best_longtext_a, hm1_best_longtext_b, hm1_best_shorttext_a, hm1_best_shorttext_b, hm1_best_common
+		} else {
+			hm_best_longtext_a, hm_best_longtext_b, hm_best_shorttext_a, hm_best_shorttext_b, hm_best_common = hm2_best_longtext_a, hm2_best_longtext_b, hm2_best_shorttext_a, hm2_best_shorttext_b, hm2_best_common
+		}
+	}
+
+	// A half-match was found, sort out the return data.
+	if len(textA) > len(textB) {
+		return hm_best_longtext_a, hm_best_longtext_b, hm_best_shorttext_a, hm_best_shorttext_b, hm_best_common
+	} else {
+		// return []string{hm[2], hm[3], hm[0], hm[1], hm[4]}
+		return hm_best_shorttext_a, hm_best_shorttext_b, hm_best_longtext_a, hm_best_longtext_b, hm_best_common
+	}
+}
+
+// * diffHalfMatchI
+func (dmp *DiffMatchPatch) DiffHalfMatchI(longtext, shorttext []rune, i int) ([]rune, []rune, []rune, []rune, []rune) {
+	// Start with a 1/4 length substring at position i as a seed.
+	seed := longtext[i : i+len(longtext)/4]
+
+	// line = safeMid(text, lineStart, lineEnd + 1 - lineStart);
+	// line := text[lineStart : lineEnd+1]
+	j := -1
+	var best_common []rune
+	var best_longtext_a, best_longtext_b []rune
+	var best_shorttext_a, best_shorttext_b []rune
+
+	for j = runesIndexOf(shorttext, seed, j+1); j != -1; j = runesIndexOf(shorttext, seed, j+1) {
+		prefixLength := dmp.DiffCommonPrefix(longtext[i:], shorttext[j:])
+		suffixLength := dmp.DiffCommonSuffix(longtext[:i], shorttext[:j])
+		if len(best_common) < suffixLength+prefixLength {
+			// A fresh slice, appending to shorttext[:j] would write into shorttext.
+			best_common = make([]rune, 0, suffixLength+prefixLength)
+			best_common = append(best_common, shorttext[j-suffixLength:j]...)
+			best_common = append(best_common, shorttext[j:j+prefixLength]...)
+			best_longtext_a = longtext[:i-suffixLength]
+			best_longtext_b = longtext[i+prefixLength:]
+			best_shorttext_a = shorttext[:j-suffixLength]
+			best_shorttext_b = shorttext[j+prefixLength:]
+		}
+	}
+	if len(best_common)*2 >= len(longtext) {
+		return best_longtext_a, best_longtext_b, best_shorttext_a, best_shorttext_b, best_common
+	} else {
+		return nil, nil, nil, nil, nil
+	}
+}
+
+// * diffCleanupSemantic
+func (dmp *DiffMatchPatch) DiffCleanupSemantic(diffs []Diff) []Diff {
+	diffs, _ = dmp.diffCleanupSemantic(diffs, 0)
+	return diffs
+}
+
+// DiffCleanupSemanticChanged is DiffCleanupSemantic which also reports
+// whether the cleanup changed diffs at all, so a caller can skip rendering
+// the same diff again.
+func (dmp *DiffMatchPatch) DiffCleanupSemanticChanged(diffs []Diff) ([]Diff, bool) {
+	return dmp.diffCleanupSemantic(diffs, 0)
+}
+
+// DiffCleanupSemanticWithContext is DiffCleanupSemantic which keeps the
+// context of the edits: equalities of at least minContext runes are never
+// merged into the edits around them, and shifting the edits never leaves
+// them shorter than minContext.  Shorter equalities may still go, the edits
+// they join keep the context around them.
+func (dmp *DiffMatchPatch) DiffCleanupSemanticWithContext(diffs []Diff, minContext int) []Diff {
+	diffs, _ = dmp.diffCleanupSemantic(diffs, max(minContext, 0))
+	return diffs
+}
+
+// DiffCleanupSemantic keeping equalities of at least minContext runes, if
+// minContext is positive.  Also reports whether anything changed.
+func (dmp *DiffMatchPatch) diffCleanupSemantic(diffs []Diff, minContext int) ([]Diff, bool) {
+	if len(diffs) == 0 {
+		return []Diff{}, false
+	}
+	changes := false
+	equalities := make([]int, 0, len(diffs)) // Stack of equalities.
+	var lastequality string                  // Always equal to equalities.lastElement().text
+	pointer := 0
+
+	// Number of runes that changed prior to the equality.
+	length_insertions1 := 0
+	length_deletions1 := 0
+	// Number of runes that changed after the equality.
+	length_insertions2 := 0
+	length_deletions2 := 0
+	for pointer < len(diffs) {
+		if diffs[pointer].Type == EQUAL {
+			// Equality found.
+			equalities = append(equalities, pointer)
+			length_insertions1 = length_insertions2
+			length_deletions1 = length_deletions2
+			length_insertions2 = 0
+			length_deletions2 = 0
+			lastequality = diffs[pointer].Text
+		} else {
+			// An insertion or deletion.
+			if diffs[pointer].Type == INSERT {
+				length_insertions2 += utf8.RuneCountInString(diffs[pointer].Text)
+			} else {
+				length_deletions2 += utf8.RuneCountInString(diffs[pointer].Text)
+			}
+			// Eliminate an equality that is smaller or equal to the edits on both
+			// sides of it.  Compare runes, bytes would favour multi-byte edits.
+			lastequalityLen := utf8.RuneCountInString(lastequality)
+			kept := minContext > 0 && lastequalityLen >= minContext
+			if !kept && lastequalityLen > 0 && lastequalityLen <= max(length_insertions1, length_deletions1) && lastequalityLen <= max(length_insertions2, length_deletions2) {
+				// printf("Splitting: '%s'\n", qPrintable(lastequality));
+				// Walk back to offending equality.
+				lastPointer := equalities[len(equalities)-1]
+				// Insert a delete before the equality.
+				diffs = diffsInsert(diffs, lastPointer, Diff{DELETE, lastequality})
+				// Change the equality into a corresponding insert.
+				diffs[lastPointer+1].Type = INSERT
+
+				equalities = equalities[:len(equalities)-1] // Throw away the equality we just deleted.
+				if len(equalities) > 0 {
+					// Throw away the previous equality (it needs to be reevaluated).
+					equalities = equalities[:len(equalities)-1]
+				}
+				pointer = -1
+				if len(equalities) > 0 {
+					// There are no previous equalities, walk back to the start.
+					pointer = equalities[len(equalities)-1]
+				}
+
+				length_insertions1 = 0 // Reset the counters.
+				length_deletions1 = 0
+				length_insertions2 = 0
+				length_deletions2 = 0
+				lastequality = ""
+				changes = true
+			}
+		}
+		pointer++
+	}
+
+	// Normalize the diff.
+	if changes {
+		_, diffs = dmp.DiffCleanupMerge(diffs)
+	}
+	diffs, shifted := dmp.diffCleanupSemanticLossless(diffs, minContext)
+	changes = changes || shifted
+
+	// Find any overlaps between deletions and insertions.
+	// e.g: <del>abcxxx</del><ins>xxxdef</ins>
+	//   -> <del>abc</del>xxx<ins>def</ins>
+	// e.g: <del>xxxabc</del><ins>defxxx</ins>
+	//   -> <ins>def</ins>xxx<del>abc</del>
+	// Only extract an overlap if it is as big as the edit ahead or behind it.
+	pointer = 1
+
+	for pointer < len(diffs) {
+		if diffs[pointer-1].Type == DELETE && diffs[pointer].Type == INSERT {
+			deletion := []rune(diffs[pointer-1].Text)
+			insertion := []rune(diffs[pointer].Text)
+			overlap_length1 := dmp.DiffCommonOverlapRunes(deletion, insertion)
+			overlap_length2 := dmp.DiffCommonOverlapRunes(insertion, deletion)
+			if overlap_length1 >= overlap_length2 {
+				if overlap_length1*2 >= len(deletion) ||
+					overlap_length1*2 >= len(insertion) {
+					// Overlap found.  Insert an equality and trim the surrounding edits.
+					diffs = diffsInsert(diffs, pointer, Diff{EQUAL, string(insertion[:overlap_length1])})
+					diffs[pointer-1].Text = string(deletion[0 : len(deletion)-overlap_length1])
+					diffs[pointer+1].Text = string(insertion[overlap_length1:])
+					pointer++
+					changes = true
+				}
+			} else {
+				if overlap_length2*2 >= len(deletion) ||
+					overlap_length2*2 >= len(insertion) {
+					// Reverse overlap found.
+					// Insert an equality and swap and trim the surrounding edits.
+					diffs = diffsInsert(diffs, pointer, Diff{EQUAL, string(deletion[:overlap_length2])})
+					diffs[pointer-1].Type = INSERT
+					diffs[pointer-1].Text = string(insertion[0 : len(insertion)-overlap_length2])
+					diffs[pointer+1].Type = DELETE
+					diffs[pointer+1].Text = string(deletion[overlap_length2:])
+					pointer++
+					changes = true
+				}
+			}
+			pointer++
+		}
+		pointer++
+	}
+	return diffs, changes
+}
+
+// DiffCleanupSemantic which also keeps numeric tokens whole.  A token is a
+// run of letters, digits and the punctuation ".,:-+_/" which holds a digit,
+// such as a number, timestamp, version or UUID.  Edits inside a token are
+// widened to the whole token, so a changed timestamp shows up as replaced
+// instead of shredded into single digits.
+func (dmp *DiffMatchPatch) DiffCleanupSemanticTokens(diffs []Diff) []Diff {
+	diffs = dmp.DiffCleanupSemantic(diffs)
+	return widenEdits(diffs, isTokenRune, func(run []rune, group editGroup) bool {
+		return containsDigit(run) || containsDigit(group.deleted) || containsDigit(group.inserted)
+	})
+}
+
+// DiffCleanupSemantic for prose, which never leaves an edit starting or
+// ending inside a word, as DiffWordMode would.  Edits which split a word are
+// widened to the whole word, then single edits are shifted a word at a time
+// to the best boundary by DiffCleanupSemanticScore.
+func (dmp *DiffMatchPatch) DiffCleanupSemanticWords(diffs []Diff) []Diff {
+	diffs = dmp.DiffCleanupSemantic(diffs)
+	diffs = widenEdits(diffs, isWordRune, func(run []rune, group editGroup) bool {
+		return true
+	})
+	return dmp.diffCleanupSemanticLosslessWords(diffs)
+}
+
+// The runes deleted and inserted between two equalities.
+type editGroup struct {
+	deleted, inserted []rune
+}
+
+// Widen each group of edits which starts or ends inside a run of isToken
+// runes to the whole run, if widen agrees.  The result has at most one
+// deletion and one insertion between equalities.
+func widenEdits(diffs []Diff, isToken func(rune) bool, widen func(run []rune, group editGroup) bool) []Diff {
+	equalities, edits := groupEdits(diffs)
+	if len(edits) == 0 {
+		return diffs
+	}
+
+	startsToken := func(text []rune) bool {
+		return len(text) > 0 && isToken(text[0])
+	}
+	endsToken := func(text []rune) bool {
+		return len(text) > 0 && isToken(text[len(text)-1])
+	}
+
+	// Widening an edit can change what widen makes of the next run, repeat
+	// until nothing moves.
+	for changes := true; changes; {
+		changes = false
+		for k := 0; k < len(edits); k++ {
+			group := &edits[k]
+
+			// The end of the equality before the edit.
+			before := equalities[k]
+			run := 0
+			for run < len(before) && isToken(before[len(before)-1-run]) {
+				run++
+			}
+			if run > 0 && (startsToken(group.deleted) || startsToken(group.inserted)) &&
+				widen(before[len(before)-run:], *group) {
+				equalities[k] = widenEditBefore(group, before, len(before)-run)
+				changes = true
+			}
+
+			// The start of the equality after the edit.
+			after := equalities[k+1]
+			run = 0
+			for run < len(after) && isToken(after[run]) {
+				run++
+			}
+			if run > 0 && (endsToken(group.deleted) || endsToken(group.inserted)) &&
+				widen(after[:run], *group) {
+				equalities[k+1] = widenEditAfter(group, after, run)
+				changes = true
+			}
+
+			if joinEdits(&equalities, &edits, k) {
+				changes = true
+			}
+		}
+	}
+
+	return ungroupEdits(equalities, edits)
+}
+
+// Split diffs into the equalities and the groups of edits between them, so
+// the text reads equalities[0] edits[0] equalities[1] ...
+// equalities[len(edits)].
+func groupEdits(diffs []Diff) ([][]rune, []editGroup) {
+	equalities := [][]rune{nil}
+	var edits []editGroup
+	inEdit := false
+	for _, aDiff := range diffs {
+		if aDiff.Type == EQUAL {
+			last := len(equalities) - 1
+			equalities[last] = append(equalities[last], []rune(aDiff.Text)...)
+			inEdit = false
+			continue
+		}
+		if !inEdit {
+			edits = append(edits, editGroup{})
+			equalities = append(equalities, nil)
+			inEdit = true
+		}
+		group := &edits[len(edits)-1]
+		if aDiff.Type == DELETE {
+			group.deleted = append(group.deleted, []rune(aDiff.Text)...)
+		} else {
+			group.inserted = append(group.inserted, []rune(aDiff.Text)...)
+		}
+	}
+	return equalities, edits
+}
+
+// Move before[from:], the end of the equality before group, into both sides
+// of group and return what is left of the equality.
+func widenEditBefore(group *editGroup, before []rune, from int) []rune {
+	group.deleted = append(slices.Clone(before[from:]), group.deleted...)
+	group.inserted = append(slices.Clone(before[from:]), group.inserted...)
+	return before[:from]
+}
+
+// Move after[:to], the start of the equality after group, into both sides
+// of group and return what is left of the equality.
+func widenEditAfter(group *editGroup, after []rune, to int) []rune {
+	group.deleted = append(group.deleted, after[:to]...)
+	group.inserted = append(group.inserted, after[:to]...)
+	return after[to:]
+}
+
+// Join edits[k] and edits[k+1] if nothing is left of the equality between
+// them, reporting whether they were joined.
+func joinEdits(equalities *[][]rune, edits *[]editGroup, k int) bool {
+	if len((*equalities)[k+1]) != 0 || k+1 >= len(*edits) {
+		return false
+	}
+	group, next := &(*edits)[k], (*edits)[k+1]
+	group.deleted = append(group.deleted, next.deleted...)
+	group.inserted = append(group.inserted, next.inserted...)
+	*edits = append((*edits)[:k+1], (*edits)[k+2:]...)
+	*equalities = append((*equalities)[:k+1], (*equalities)[k+2:]...)
+	return true
+}
+
+// Turn the groups of groupEdits back into diffs.  The edits are not merged
+// again, that would factor the widened text apart.
+func ungroupEdits(equalities [][]rune, edits []editGroup) []Diff {
+	diffs := []Diff{}
+	for k, equality := range equalities {
+		if len(equality) > 0 {
+			diffs = append(diffs, Diff{EQUAL, string(equality)})
+		}
+		if k < len(edits) {
+			if len(edits[k].deleted) > 0 {
+				diffs = append(diffs, Diff{DELETE, string(edits[k].deleted)})
+			}
+			if len(edits[k].inserted) > 0 {
+				diffs = append(diffs, Diff{INSERT, string(edits[k].inserted)})
+			}
+		}
+	}
+	return diffs
+}
+
+// DiffCleanupTags widens edits which start or end inside an HTML tag to the
+// whole tag, so diffs of markup never split a tag, e.g. <d and el>.  A tag
+// runs from a '<' followed by a letter, '/', '!' or '?' to the next '>'.
+// Run it after the other cleanups.
+func (dmp *DiffMatchPatch) DiffCleanupTags(diffs []Diff) []Diff {
+	equalities, edits := groupEdits(diffs)
+	if len(edits) == 0 {
+		return diffs
+	}
+
+	// Widening into the next equality can reach the next edit, repeat until
+	// nothing moves.
+	for changes := true; changes; {
+		changes = false
+		for k := 0; k < len(edits); k++ {
+			group := &edits[k]
+
+			// A tag opened in the equality before the edit.
+			before := equalities[k]
+			if open := lastOpenTag(before); open != -1 {
+				equalities[k] = widenEditBefore(group, before, open)
+				changes = true
+			}
+
+			// A tag opened in the edit, closed in the equality after it or
+			// beyond.
+			after := equalities[k+1]
+			if len(after) > 0 && (lastOpenTag(group.deleted) != -1 || lastOpenTag(group.inserted) != -1) {
+				end := slices.Index(after, '>') + 1
+				if end == 0 {
+					end = len(after)
+				}
+				equalities[k+1] = widenEditAfter(group, after, end)
+				changes = true
+			}
+
+			if joinEdits(&equalities, &edits, k) {
+				changes = true
+			}
+		}
+	}
+
+	return ungroupEdits(equalities, edits)
+}
+
+// The index of the '<' of a tag which text leaves open, -1 if none.  A '<'
+// at the very end may start a tag, whatever comes next.
+func lastOpenTag(text []rune) int {
+	for i := len(text) - 1; i >= 0; i-- {
+		switch {
+		case text[i] == '>':
+			return -1
+		case text[i] == '<' && (i+1 == len(text) || unicode.IsLetter(text[i+1]) || strings.ContainsRune("/!?", text[i+1])):
+			return i
+		}
+	}
+	return -1
+}
+
+// DiffCleanupSemanticLossless moving whole words instead of runes, for
+// diffs whose edits start and end on word boundaries.
+func (dmp *DiffMatchPatch) diffCleanupSemanticLosslessWords(diffs []Diff) []Diff {
+	// Intentionally ignore the first and last element (don't need checking).
+	for pointer := 1; pointer < len(diffs)-1; pointer++ {
+		if diffs[pointer-1].Type != EQUAL || diffs[pointer+1].Type != EQUAL {
+			continue
+		}
+		// This is a single edit surrounded by equalities.
+		equality1 := diffs[pointer-1].Text
+		edit := diffs[pointer].Text
+		equality2 := diffs[pointer+1].Text
+
+		// First, shift the edit as far left as possible.
+		for {
+			word := lastWord(edit)
+			if len(word) == 0 || lastWord(equality1) != word {
+				break
+			}
+			equality1 = equality1[:len(equality1)-len(word)]
+			edit = word + edit[:len(edit)-len(word)]
+			equality2 = word + equality2
+		}
+
+		// Second, step word by word right, looking for the best fit.
+		bestEquality1 := equality1
+		bestEdit := edit
+		bestEquality2 := equality2
+		bestScore := dmp.DiffCleanupSemanticScore(equality1, edit) +
+			dmp.DiffCleanupSemanticScore(edit, equality2)
+		for {
+			word := firstWord(edit)
+			if len(word) == 0 || firstWord(equality2) != word {
+				break
+			}
+			equality1 += word
+			edit = edit[len(word):] + word
+			equality2 = equality2[len(word):]
+			score := dmp.DiffCleanupSemanticScore(equality1, edit) +
+				dmp.DiffCleanupSemanticScore(edit, equality2)
+			// The >= encourages trailing rather than leading whitespace on edits.
+			if score >= bestScore {
+				bestScore = score
+				bestEquality1 = equality1
+				bestEdit = edit
+				bestEquality2 = equality2
+			}
+		}
+
+		if diffs[pointer-1].Text != bestEquality1 {
+			// We have an improvement, save it back to the diff.
+			if len(bestEquality1) > 0 {
+				diffs[pointer-1].Text = bestEquality1
+			} else {
+				diffs = append(diffs[:pointer-1], diffs[pointer:]...)
+				pointer--
+			}
+			diffs[pointer].Text = bestEdit
+			if len(bestEquality2) > 0 {
+				diffs[pointer+1].Text = bestEquality2
+			} else {
+				diffs = append(diffs[:pointer+1], diffs[pointer+2:]...)
+				pointer--
+			}
+		}
+	}
+	return diffs
+}
+
+// The word or run of separators text starts with, as DiffWordsToCharsMunge
+// splits it.
+func firstWord(text string) string {
+	if len(text) == 0 {
+		return ""
+	}
+	r, end := utf8.DecodeRuneInString(text)
+	inWord := isWordRune(r)
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(r) != inWord {
+			break
+		}
+		end += size
+	}
+	return text[:end]
+}
+
+// The word or run of separators text ends with.
+func lastWord(text string) string {
+	if len(text) == 0 {
+		return ""
+	}
+	r, size := utf8.DecodeLastRuneInString(text)
+	inWord := isWordRune(r)
+	start := len(text) - size
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if isWordRune(r) != inWord {
+			break
+		}
+		start -= size
+	}
+	return text[start:]
+}
+
+// Runes which can be part of a numeric token.
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(".,:-+_/", r)
+}
+
+func containsDigit(text []rune) bool {
+	return slices.ContainsFunc(text, unicode.IsDigit)
+}
+
+// Insert d into diffs at index, shifting the tail up by one.
+func diffsInsert(diffs []Diff, index int, d Diff) []Diff {
+	diffs = append(diffs, Diff{})
+	copy(diffs[index+1:], diffs[index:])
+	diffs[index] = d
+	return diffs
+}
+
+// * diffCleanupSemanticLossless
+func (dmp *DiffMatchPatch) DiffCleanupSemanticLossless(diffs []Diff) []Diff {
+	diffs, _ = dmp.diffCleanupSemanticLossless(diffs, 0)
+	return diffs
+}
+
+// DiffCleanupSemanticLossless which doesn't shift an edit so far that an
+// equality next to it drops below minContext runes, or below its own length
+// if that is shorter.  Also reports whether any edit was shifted.
+func (dmp *DiffMatchPatch) diffCleanupSemanticLossless(diffs []Diff, minContext int) ([]Diff, bool) {
+	changes := false
+	var equality1, edit, equality2 string
+	// Create a new iterator at the start.
+	pointer := 1
+
+	// Intentionally ignore the first and last element (don't need checking).
+	for pointer < len(diffs)-1 {
+		if diffs[pointer-1].Type == EQUAL &&
+			diffs[pointer+1].Type == EQUAL {
+			// This is a single edit surrounded by equalities.
+			equality1 = diffs[pointer-1].Text
+			edit = diffs[pointer].Text
+			equality2 = diffs[pointer+1].Text
+
+			// The rune lengths of the equalities, and how short each may get.
+			equality1Len := utf8.RuneCountInString(equality1)
+			equality2Len := utf8.RuneCountInString(equality2)
+			min1, min2 := 0, 0
+			if minContext > 0 {
+				min1 = min(minContext, equality1Len)
+				min2 = min(minContext, equality2Len)
+			}
+
+			// First, shift the edit as far left as possible.
+			if commonRunes := dmp.DiffCommonSuffixString(equality1, edit); commonRunes > 0 {
+				// The suffix length counts runes, slicing needs bytes.
+				editRunes := []rune(edit)
+				commonString := string(editRunes[len(editRunes)-commonRunes:])
+				commonOffset := len(commonString)
+				equality1 = equality1[:len(equality1)-commonOffset]
+				edit = commonString + edit[:len(edit)-commonOffset]
+				equality2 = commonString + equality2
+				equality1Len -= commonRunes
+				equality2Len += commonRunes
+			}
+
+			// Second, step character by character right, looking for the best fit
+			// among the shifts which leave enough context.  The edit's own place
+			// always does.
+			bestEquality1 := equality1
+			bestEdit := edit
+			bestEquality2 := equality2
+			bestScore := -1
+			if equality1Len >= min1 && equality2Len >= min2 {
+				bestScore = dmp.DiffCleanupSemanticScore(equality1, edit) +
+					dmp.DiffCleanupSemanticScore(edit, equality2)
+			}
+			for len(edit) != 0 && len(equality2) != 0 &&
+				edit[0] == equality2[0] {
+				_, sz := utf8.DecodeRuneInString(edit)
+				if len(equality2) < sz || edit[:sz] != equality2[:sz] {
+					break
+				}
+				equality1 += edit[:sz]
+				edit = edit[sz:] + equality2[:sz]
+				equality2 = equality2[sz:]
+				equality1Len++
+				equality2Len--
+				if equality1Len < min1 || equality2Len < min2 {
+					continue
+				}
+				score := dmp.DiffCleanupSemanticScore(equality1, edit) +
+					dmp.DiffCleanupSemanticScore(edit, equality2)
+				// The >= encourages trailing rather than leading whitespace on edits.
+				if score >= bestScore {
+					bestScore = score
+					bestEquality1 = equality1
+					bestEdit = edit
+					bestEquality2 = equality2
+				}
+			}
+
+			if diffs[pointer-1].Text != bestEquality1 {
+				// We have an improvement, save it back to the diff.
+				if len(bestEquality1) > 0 {
+					diffs[pointer-1].Text = bestEquality1
+				} else {
+					diffs = append(diffs[:pointer-1], diffs[pointer:]...)
+					pointer--
+				}
+				diffs[pointer].Text = bestEdit
+				if len(bestEquality2) > 0 {
+					diffs[pointer+1].Text = bestEquality2
+				} else {
+					diffs = append(diffs[:pointer+1], diffs[pointer+2:]...)
+					pointer--
+				}
+				changes = true
+			}
+		}
+		pointer++
+	}
+	return diffs, changes
+}
+
+// The character classes of DiffCleanupSemanticScore, checked on the rune
+// itself rather than matching a regexp against it.  Letters and digits of
+// any script count as alphanumeric, so accented or CJK words aren't split.
+
+func isAlphaNumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isWhitespace(r rune) bool {
+	return unicode.IsSpace(r)
+}
+
+// Matches [\r\n].
+func isLineBreak(r rune) bool {
+	return r == '\r' || r == '\n'
+}
+
+// Matches \n\r?\n$.
+func hasBlankLineEnd(text string) bool {
+	return strings.HasSuffix(text, "\n\n") || strings.HasSuffix(text, "\n\r\n")
+}
+
+// * diffCleanupSemanticScore
+func (dmp *DiffMatchPatch) DiffCleanupSemanticScore(one, two string) int {
+	if len(one) == 0 || len(two) == 0 {
+		// Edges are the best.
+		return 6
+	}
+
+	// Each port of this function behaves slightly differently due to
+	// subtle differences in each language's definition of things like
+	// 'whitespace'.  Since this function's purpose is largely cosmetic,
+	// the choice has been made to use each language's native features
+	// rather than force total conformity.
+	char1, _ := utf8.DecodeLastRuneInString(one)
+	char2, _ := utf8.DecodeRuneInString(two)
+
+	nonAlphaNumeric1 := !isAlphaNumeric(char1)
+	nonAlphaNumeric2 := !isAlphaNumeric(char2)
+	whitespace1 := nonAlphaNumeric1 && isWhitespace(char1)
+	whitespace2 := nonAlphaNumeric2 && isWhitespace(char2)
+	lineBreak1 := whitespace1 && isLineBreak(char1)
+	lineBreak2 := whitespace2 && isLineBreak(char2)
+	blankLine1 := lineBreak1 && hasBlankLineEnd(one)
+	blankLine2 := lineBreak2 && hasBlankLineEnd(two)
+
+	if blankLine1 || blankLine2 {
+		// Five points for blank lines.
+		return 5
+	} else if lineBreak1 || lineBreak2 {
+		// Four points for line breaks.
+		return 4
+	} else if nonAlphaNumeric1 && !whitespace1 && whitespace2 {
+		// Three points for end of sentences.
+		return 3
+	} else if whitespace1 || whitespace2 {
+		// Two points for whitespace.
+		return 2
+	} else if nonAlphaNumeric1 || nonAlphaNumeric2 {
+		// One point for non-alphanumeric.
+		return 1
+	}
+	return 0
+}
+
+// * diffCleanupEfficiency - used by patch, skip
+func (dmp *DiffMatchPatch) DiffCleanupEfficiency(diffs []Diff) []Diff {
+	changes := false
+	// Stack of indices where equalities are found.
+	type equality struct {
+		data int
+		next *equality
+	}
+	var equalities *equality
+	// Always equal to equalities[equalitiesLength-1][1]
+	lastequality := ""
+	pointer := 0 // Index of current position.
+	// Is there an insertion operation before the last equality.
+	preIns := false
+	// Is there a deletion operation before the last equality.
+	preDel := false
+	// Is there an insertion operation after the last equality.
+	postIns := false
+	// Is there a deletion operation after the last equality.
+	postDel := false
+	for pointer < len(diffs) {
+		if diffs[pointer].Type == EQUAL { // Equality found.
+			if utf8.RuneCountInString(diffs[pointer].Text) < int(dmp.Diff_EditCost) &&
+				(postIns || postDel) {
+				// Candidate found.
+				equalities = &equality{
+					data: pointer,
+					next: equalities,
+				}
+				preIns = postIns
+				preDel = postDel
+				lastequality = diffs[pointer].Text
+			} else {
+				// Not a candidate, and can never become one.
+				equalities = nil
+				lastequality = ""
+			}
+			postIns = false
+			postDel = false
+		} else { // An insertion or deletion.
+			if diffs[pointer].Type == DELETE {
+				postDel = true
+			} else {
+				postIns = true
+			}
+
+			// Five types to be split:
+			// <ins>A</ins><del>B</del>XY<ins>C</ins><del>D</del>
+			// <ins>A</ins>X<ins>C</ins><del>D</del>
+			// <ins>A</ins><del>B</del>X<ins>C</ins>
+			// <ins>A</del>X<ins>C</ins><del>D</del>
+			// <ins>A</ins><del>B</del>X<del>C</del>
+			var sumPres int
+			if preIns {
+				sumPres++
+			}
+			if preDel {
+				sumPres++
+			}
+			if postIns {
+				sumPres++
+			}
+			if postDel {
+				sumPres++
+			}
+			if len(lastequality) > 0 &&
+				((preIns && preDel && postIns && postDel) ||
+					((utf8.RuneCountInString(lastequality) < int(dmp.Diff_EditCost)/2) && sumPres == 3)) {
+
+				insPoint := equalities.data
+
+				// Duplicate record.
+				diffs = append(diffs[:insPoint], append([]Diff{Diff{DELETE, lastequality}},
+					diffs[insPoint:]...)...)
+
+				// Change second copy to insert.
+				diffs[insPoint+1].Type = INSERT
+				// Throw away the equality we just deleted.
+				equalities = equalities.next
+				lastequality = ""
+
+				if preIns && preDel {
+					// No changes made which could affect previous entry, keep going.
+					postIns = true
+					postDel = true
+					equalities = nil
+				} else {
+					if equalities != nil {
+						equalities = equalities.next
+					}
+					if equalities != nil {
+						pointer = equalities.data
+					} else {
+						pointer = -1
+					}
+					postIns = false
+					postDel = false
+				}
+				changes = true
+			}
+		}
+		pointer++
+	}
+
+	if changes {
+		_, diffs = dmp.DiffCleanupMerge(diffs)
+	}
+
+	return diffs
+}
+
+// Merge the edits around every equality of fewer than maxEqual runes into
+// one change, for display, so a run of edits isn't chopped up by the odd
+// matching rune.  Unlike DiffCleanupEfficiency and DiffCleanupSemantic this
+// only looks at the length of the equality, not at the edits around it.
+func (dmp *DiffMatchPatch) DiffMergeShortEqualities(diffs []Diff, maxEqual int) []Diff {
+	merged := make([]Diff, 0, len(diffs))
+	changes := false
+	for i, aDiff := range diffs {
+		if aDiff.Type == EQUAL && i > 0 && i < len(diffs)-1 &&
+			diffs[i-1].Type != EQUAL && diffs[i+1].Type != EQUAL &&
+			utf8.RuneCountInString(aDiff.Text) < maxEqual {
+			// Between two edits, delete it and insert it again.
+			merged = append(merged, Diff{DELETE, aDiff.Text}, Diff{INSERT, aDiff.Text})
+			changes = true
+			continue
+		}
+		merged = append(merged, aDiff)
+	}
+	if changes {
+		_, merged = dmp.DiffCleanupMerge(merged)
+	}
+	return merged
+}
+
+// Minimum length in runes of a moved block for DiffDetectMoves, shorter
+// texts are too likely to match by chance.
+const diffMoveMinRunes = 4
+
+// Find blocks of text which were moved: a deletion whose text is inserted
+// elsewhere in the diff.  The insertion becomes a MOVE, the deletion stays,
+// so the texts of the diff don't change.  Only whole deletions and
+// insertions of at least a few runes which aren't just whitespace count.
+// Run this last, the cleanups merge MOVE into neighbouring insertions and
+// the patch functions only take DELETE, INSERT and EQUAL.
+func (dmp *DiffMatchPatch) DiffDetectMoves(diffs []Diff) []Diff {
+	// Unpaired insertions by text.
+	insertions := make(map[string][]int)
+	for i, aDiff := range diffs {
+		if aDiff.Type == INSERT && isMoveCandidate(aDiff.Text) {
+			insertions[aDiff.Text] = append(insertions[aDiff.Text], i)
+		}
+	}
+	for _, aDiff := range diffs {
+		if aDiff.Type != DELETE {
+			continue
+		}
+		if candidates := insertions[aDiff.Text]; len(candidates) > 0 {
+			diffs[candidates[0]].Type = MOVE
+			insertions[aDiff.Text] = candidates[1:]
+		}
+	}
+	return diffs
+}
+
+func isMoveCandidate(text string) bool {
+	return utf8.RuneCountInString(text) >= diffMoveMinRunes && strings.TrimFunc(text, unicode.IsSpace) != ""
+}
+
+// * diff_xIndex
+// loc is a location in the source text, compute and return the equivalent
+// location in the result text. e.g. "The cat" vs "The big cat", 1->1, 5->8
+// Locations count runes.
+func (dmp *DiffMatchPatch) DiffXIndex(diffs []Diff, loc int) int {
+	chars1 := 0
+	chars2 := 0
+	lastChars1 := 0
+	lastChars2 := 0
+	lastDiff := Diff{}
+	for _, aDiff := range diffs {
+		textLen := utf8.RuneCountInString(aDiff.Text)
+		if aDiff.Type != INSERT && aDiff.Type != MOVE {
+			// Equality or deletion.
+			chars1 += textLen
+		}
+		if aDiff.Type != DELETE {
+			// Equality or insertion.
+			chars2 += textLen
+		}
+		if chars1 > loc {
+			// Overshot the location.
+			lastDiff = aDiff
+			break
+		}
+		lastChars1 = chars1
+		lastChars2 = chars2
+	}
+	if lastDiff.Type == DELETE {
+		// The location was deleted.
+		return lastChars2
+	}
+	// Add the remaining character length.
+	return lastChars2 + (loc - lastChars1)
+}
+
+// * diff_levenshtein
+// Compute the Levenshtein distance; the number of inserted, deleted or
+// substituted runes.
+func (dmp *DiffMatchPatch) DiffLevenshtein(diffs []Diff) int {
+	levenshtein := 0
+	insertions := 0
+	deletions := 0
+	for _, aDiff := range diffs {
+		switch aDiff.Type {
+		case INSERT, MOVE:
+			insertions += utf8.RuneCountInString(aDiff.Text)
+		case DELETE:
+			deletions += utf8.RuneCountInString(aDiff.Text)
+		case EQUAL:
+			// A deletion and an insertion is one substitution.
+			levenshtein += max(insertions, deletions)
+			insertions = 0
+			deletions = 0
+		}
+	}
+	levenshtein += max(insertions, deletions)
+	return levenshtein
+}
+
+// * diffCleanupMerge
+func (dmp *DiffMatchPatch) DiffCleanupMerge(diffs []Diff) (error, []Diff) {
+	// Sweep again until nothing shifts.  A sweep which shifts an edit or
+	// drops an empty one leaves fewer diffs, so this ends, and unlike
+	// recursing it doesn't grow the stack on long chains of shifts.
+	for {
+		err, merged, changes := dmp.diffCleanupMergeSweep(diffs)
+		if err != nil || !changes {
+			return err, merged
+		}
+		diffs = merged
+	}
+}
+
+// One sweep of DiffCleanupMerge, reporting whether any edit was shifted or
+// dropped, in which case the diff needs reordering and another sweep.
+func (dmp *DiffMatchPatch) diffCleanupMergeSweep(diffs []Diff) (error, []Diff, bool) {
+	diffs = append(diffs, Diff{EQUAL, ""}) // Add a dummy entry at the end.
+	pointer := 0
+	count_delete := 0
+	count_insert := 0
+	text_delete := []rune{}
+	text_insert := []rune{}
+	var commonlength int
+	for pointer < len(diffs) {
+		switch diffs[pointer].Type {
+		case INSERT, MOVE:
+			count_insert++
+			text_insert = append(text_insert, []rune(diffs[pointer].Text)...)
+			pointer++
+			break
+		case DELETE:
+			count_delete++
+			text_delete = append(text_delete, []rune(diffs[pointer].Text)...)
+			pointer++
+			break
+		case EQUAL:
+			if count_delete+count_insert > 1 {
+				both_types := count_delete != 0 && count_insert != 0
+				// Index of the first of the offending records.
+				tempPointer := pointer - count_delete - count_insert
+				if both_types {
+					// Factor out any common prefixies.
+					commonlength = dmp.DiffCommonPrefix(text_insert, text_delete)
+					if commonlength != 0 {
+						if tempPointer > 0 {
+							if diffs[tempPointer-1].Type != EQUAL {
+								return errors.New("Previous diff should have been an equality."), nil, false
+							}
+							diffs[tempPointer-1].Text += string(text_insert[:commonlength])
+						} else {
+							diffs = append([]Diff{{EQUAL, string(text_insert[:commonlength])}}, diffs...)
+							pointer++
+						}
+						text_insert = text_insert[commonlength:]
+						text_delete = text_delete[commonlength:]
+					}
+					// Factor out any common suffixies.
+					commonlength = dmp.DiffCommonSuffix(text_insert, text_delete)
+					if commonlength != 0 {
+						diffs[pointer].Text = string(text_insert[len(text_insert)-commonlength:]) + diffs[pointer].Text
+						text_insert = text_insert[:len(text_insert)-commonlength]
+						text_delete = text_delete[:len(text_delete)-commonlength]
+					}
+				}
+				// Delete the offending records and add the merged ones.
+				pointer -= count_delete + count_insert
+				diffs = append(diffs[:pointer], diffs[pointer+count_delete+count_insert:]...)
+				if len(text_delete) != 0 {
+					diffs = diffsInsert(diffs, pointer, Diff{DELETE, string(text_delete)})
+					pointer++
+				}
+				if len(text_insert) != 0 {
+					diffs = diffsInsert(diffs, pointer, Diff{INSERT, string(text_insert)})
+					pointer++
+				}
+			}
+			if pointer > 0 && diffs[pointer-1].Type == EQUAL {
+				// The edits cancelled out or there were none.
+				// Merge this equality with the previous one.
+				diffs[pointer-1].Text += diffs[pointer].Text
+				diffs = append(diffs[:pointer], diffs[pointer+1:]...)
+			} else {
+				pointer++
+			}
+			count_insert = 0
+			count_delete = 0
+			text_delete = []rune{}
+			text_insert = []rune{}
+			break
+		}
+	}
+	if len(diffs) > 0 && diffs[len(diffs)-1].Type == EQUAL && len(diffs[len(diffs)-1].Text) == 0 {
+		diffs = diffs[0 : len(diffs)-1] // Remove the dummy entry at the end.
+	}
+
+	/*
+	 * Second pass: look for single edits surrounded on both sides by equalities
+	 * which can be shifted sideways to eliminate an equality.
+	 * e.g: A<ins>BA</ins>C -> <ins>AB</ins>AC
+	 */
+	changes := false
+	// Create a new iterator at the start.
+	// (As opposed to walking the current one back.)
+	pointer = 1
+
+	// Intentionally ignore the first and last element (don't need checking).
+	for pointer < len(diffs)-1 {
+		if diffs[pointer-1].Type == EQUAL &&
+			diffs[pointer+1].Type == EQUAL {
+			// This is a single edit surrounded by equalities.
+			if strings.HasSuffix(diffs[pointer].Text, diffs[pointer-1].Text) {
+				// Shift the edit over the previous equality.
+				diffs[pointer].Text = diffs[pointer-1].Text +
+					diffs[pointer].Text[:len(diffs[pointer].Text)-
+						len(diffs[pointer-1].Text)]
+				diffs[pointer+1].Text = diffs[pointer-1].Text + diffs[pointer+1].Text
+				// Delete prevDiff.
+				diffs = append(diffs[:pointer-1], diffs[pointer:]...)
+				changes = true
+			} else if strings.HasPrefix(diffs[pointer].Text, diffs[pointer+1].Text) {
+				// Shift the edit over the next equality.
+				diffs[pointer-1].Text += diffs[pointer+1].Text
+				diffs[pointer].Text = diffs[pointer].Text[len(diffs[pointer+1].Text):] + diffs[pointer+1].Text
+				diffs = append(diffs[:pointer+1], diffs[pointer+2:]...)
+				changes = true
+			}
+		}
+		pointer++
+	}
+	// Final sweep: drop edits with no text, e.g. a lone empty insertion which
+	// the first pass leaves alone.
+	cleaned := diffs[:0]
+	for _, diff := range diffs {
+		if diff.Type != EQUAL && len(diff.Text) == 0 {
+			// The equalities either side may now need merging.
+			changes = true
+			continue
+		}
+		cleaned = append(cleaned, diff)
+	}
+	diffs = cleaned
+	return nil, diffs, changes
+}
+
+// * diff_prettyHtml
+// Convert a diff array into a pretty HTML report.
+func DiffPrettyHtml(diffs []Diff) string {
+	return DiffPrettyHtmlOpts(diffs, true)
+}
+
+// Convert a diff array into a pretty HTML report.  Line breaks are marked as
+// "&para;<br>" if showParagraphMarks is set, otherwise newlines are left as
+// they are, e.g. for use inside a <pre> block.
+func DiffPrettyHtmlOpts(diffs []Diff, showParagraphMarks bool) string {
+	var buffer bytes.Buffer
+	for _, diff := range diffs {
+		writePrettyHtml(&buffer, diff, showParagraphMarks)
+	}
+	return buffer.String()
+}
+
+// Convert a diff array into a pretty HTML report like DiffPrettyHtml, but
+// with each deletion followed by an insertion wrapped in one
+// <span class="diff-replace">, the old text struck through before the new,
+// so substitutions read as one change.
+func DiffPrettyHtmlReplace(diffs []Diff) string {
+	var buffer bytes.Buffer
+	for i := 0; i < len(diffs); i++ {
+		if diffs[i].Type == DELETE && i+1 < len(diffs) && diffs[i+1].Type == INSERT {
+			_, _ = buffer.WriteString("<span class=\"diff-replace\">")
+			writePrettyHtml(&buffer, diffs[i], true)
+			writePrettyHtml(&buffer, diffs[i+1], true)
+			_, _ = buffer.WriteString("</span>")
+			i++
+			continue
+		}
+		writePrettyHtml(&buffer, diffs[i], true)
+	}
+	return buffer.String()
+}
+
+// Write one diff of DiffPrettyHtmlOpts to buffer.
+func writePrettyHtml(buffer *bytes.Buffer, diff Diff, showParagraphMarks bool) {
+	text := prettyHtmlText(diff.Text, showParagraphMarks)
+	switch diff.Type {
+	case INSERT:
+		_, _ = buffer.WriteString("<ins style=\"background:#e6ffe6;\">")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</ins>")
+	case MOVE:
+		_, _ = buffer.WriteString("<ins style=\"background:#e6f0ff;\">")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</ins>")
+	case DELETE:
+		_, _ = buffer.WriteString("<del style=\"background:#ffe6e6;\">")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</del>")
+	case EQUAL:
+		_, _ = buffer.WriteString("<span>")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</span>")
+	}
+}
+
+// Convert a diff array into an HTML report styled through the classes
+// "diff-ins", "diff-del", "diff-eq" and "diff-move" rather than inline
+// styles, for pages whose Content-Security-Policy forbids those.
+func DiffPrettyHtmlClasses(diffs []Diff) string {
+	var buffer bytes.Buffer
+	for _, diff := range diffs {
+		text := prettyHtmlText(diff.Text, true)
+		switch diff.Type {
+		case INSERT:
+			_, _ = buffer.WriteString("<ins class=\"diff-ins\">")
+			_, _ = buffer.WriteString(text)
+			_, _ = buffer.WriteString("</ins>")
+		case MOVE:
+			_, _ = buffer.WriteString("<ins class=\"diff-move\">")
+			_, _ = buffer.WriteString(text)
+			_, _ = buffer.WriteString("</ins>")
+		case DELETE:
+			_, _ = buffer.WriteString("<del class=\"diff-del\">")
+			_, _ = buffer.WriteString(text)
+			_, _ = buffer.WriteString("</del>")
+		case EQUAL:
+			_, _ = buffer.WriteString("<span class=\"diff-eq\">")
+			_, _ = buffer.WriteString(text)
+			_, _ = buffer.WriteString("</span>")
+		}
+	}
+	return buffer.String()
+}
+
+// Convert a diff array into two aligned HTML columns for a side-by-side
+// view, the old text with its deletions and the new text with its
+// insertions.  Every line is a <div> with its line number, and a line with
+// no counterpart gets an empty placeholder row in the other column.  Lines
+// touched by an edit are shown as changed in full, so line mode diffs give
+// the best results.  Put the columns in <pre> blocks to keep indentation.
+func DiffToSideBySideHtml(diffs []Diff) (string, string) {
+	var left, right bytes.Buffer
+	for _, row := range alignLines(diffs) {
+		if row.numberA > 0 {
+			sideBySideRow(&left, row.numberA, row.lineA)
+		} else {
+			_, _ = left.WriteString("<div>&nbsp;</div>")
+		}
+		if row.numberB > 0 {
+			sideBySideRow(&right, row.numberB, row.lineB)
+		} else {
+			_, _ = right.WriteString("<div>&nbsp;</div>")
+		}
+	}
+	return left.String(), right.String()
+}
+
+// Convert a diff array into a <table class="diff-table"> for a code review
+// page, each row holding the number and content of an old line and of the
+// new line beside it.  Rows are classed "diff-eq", "diff-del", "diff-ins"
+// or "diff-change" for a deleted line facing an inserted one, and a line
+// with no counterpart faces empty cells.  The cells are classed "diff-num",
+// "diff-old" and "diff-new"; give the content cells white-space: pre to keep
+// indentation.  As with DiffToSideBySideHtml, line mode diffs give the best
+// results.
+func DiffToHTMLTable(diffs []Diff) string {
+	var buffer bytes.Buffer
+	_, _ = buffer.WriteString("<table class=\"diff-table\">")
+	for _, row := range alignLines(diffs) {
+		class := "diff-change"
+		switch {
+		case row.lineA.Type == EQUAL:
+			class = "diff-eq"
+		case row.numberB == 0:
+			class = "diff-del"
+		case row.numberA == 0:
+			class = "diff-ins"
+		}
+		_, _ = buffer.WriteString("<tr class=\"" + class + "\">")
+		tableCells(&buffer, row.numberA, row.lineA, "diff-old")
+		tableCells(&buffer, row.numberB, row.lineB, "diff-new")
+		_, _ = buffer.WriteString("</tr>")
+	}
+	_, _ = buffer.WriteString("</table>")
+	return buffer.String()
+}
+
+// Write the number and content cells of one side of a table row, empty if
+// number is 0.
+func tableCells(buffer *bytes.Buffer, number int, line unifiedLine, class string) {
+	_, _ = buffer.WriteString("<td class=\"diff-num\">")
+	if number > 0 {
+		_, _ = buffer.WriteString(strconv.Itoa(number))
+	}
+	_, _ = buffer.WriteString("</td><td class=\"" + class + "\">")
+	if number > 0 {
+		_, _ = buffer.WriteString(prettyHtmlText(strings.TrimSuffix(line.Text, "\n"), false))
+	}
+	_, _ = buffer.WriteString("</td>")
+}
+
+// One row of a side-by-side view: a line of the old text and the line of
+// the new text beside it, with their 1-based numbers.  A number is 0 if
+// that side has no line in the row.
+type alignedLine struct {
+	numberA, numberB int
+	lineA, lineB     unifiedLine
+}
+
+// Pair the lines of diffs up for a side-by-side view.  Unchanged lines face
+// each other, and in each block of changes the deleted lines face the
+// inserted ones, the longer side facing empty rows past the shorter.
+func alignLines(diffs []Diff) []alignedLine {
+	var rows []alignedLine
+	lineA, lineB := 0, 0
+	lines := diffLines(diffs)
+	pointer := 0
+	for pointer < len(lines) {
+		if lines[pointer].Type == EQUAL {
+			lineA++
+			lineB++
+			rows = append(rows, alignedLine{lineA, lineB, lines[pointer], lines[pointer]})
+			pointer++
+			continue
+		}
+
+		// A block of changed lines, the deletions come first.
+		deleted := pointer
+		for pointer < len(lines) && lines[pointer].Type == DELETE {
+			pointer++
+		}
+		inserted := pointer
+		for pointer < len(lines) && lines[pointer].Type == INSERT {
+			pointer++
+		}
+		for i := 0; i < max(inserted-deleted, pointer-inserted); i++ {
+			var row alignedLine
+			if deleted+i < inserted {
+				lineA++
+				row.numberA, row.lineA = lineA, lines[deleted+i]
+			}
+			if inserted+i < pointer {
+				lineB++
+				row.numberB, row.lineB = lineB, lines[inserted+i]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Write one line of a side-by-side column.
+func sideBySideRow(buffer *bytes.Buffer, number int, line unifiedLine) {
+	_, _ = buffer.WriteString("<div><span style=\"color:#999;\">")
+	_, _ = buffer.WriteString(strconv.Itoa(number))
+	_, _ = buffer.WriteString("</span> ")
+	text := prettyHtmlText(strings.TrimSuffix(line.Text, "\n"), false)
+	switch line.Type {
+	case INSERT:
+		_, _ = buffer.WriteString("<ins style=\"background:#e6ffe6;\">")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</ins>")
+	case DELETE:
+		_, _ = buffer.WriteString("<del style=\"background:#ffe6e6;\">")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</del>")
+	case EQUAL:
+		_, _ = buffer.WriteString("<span>")
+		_, _ = buffer.WriteString(text)
+		_, _ = buffer.WriteString("</span>")
+	}
+	_, _ = buffer.WriteString("</div>")
+}
+
+// Escape text for the HTML reports, optionally marking line breaks with a
+// pilcrow.
+func prettyHtmlText(text string, showParagraphMarks bool) string {
+	text = html.EscapeString(text)
+	if showParagraphMarks {
+		text = strings.Replace(text, "\n", "&para;<br>", -1)
+	}
+	return text
+}
+
+// Convert a diff array into a Markdown ```diff block, with every line
+// prefixed by "+", "-" or a space, which GitHub and many chat clients
+// highlight.  Lines touched by an edit are shown as deleted and inserted in
+// full, so line mode diffs give the best results.
+func DiffPrettyMarkdown(diffs []Diff) string {
+	lines := diffLines(diffs)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	// The fence has to be longer than any run of backticks in the text.
+	fence := 3
+	for _, aDiff := range diffs {
+		run := 0
+		for _, r := range aDiff.Text {
+			if r == '`' {
+				run++
+				fence = max(fence, run+1)
+			} else {
+				run = 0
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	_, _ = buffer.WriteString(strings.Repeat("`", fence) + "diff\n")
+	for _, line := range lines {
+		switch line.Type {
+		case INSERT:
+			_, _ = buffer.WriteString("+")
+		case DELETE:
+			_, _ = buffer.WriteString("-")
+		case EQUAL:
+			_, _ = buffer.WriteString(" ")
+		}
+		_, _ = buffer.WriteString(strings.TrimSuffix(line.Text, "\n"))
+		_, _ = buffer.WriteString("\n")
+	}
+	_, _ = buffer.WriteString(strings.Repeat("`", fence) + "\n")
+	return buffer.String()
+}
+
+// Convert a diff array into text wrapped in ANSI color codes for terminals,
+// green for insertions, red for deletions and blue for moved text.
+func DiffPrettyText(diffs []Diff) string {
+	var buffer bytes.Buffer
+	for _, diff := range diffs {
+		switch diff.Type {
+		case INSERT:
+			_, _ = buffer.WriteString("\x1b[32m")
+			_, _ = buffer.WriteString(diff.Text)
+			_, _ = buffer.WriteString("\x1b[0m")
+		case MOVE:
+			_, _ = buffer.WriteString("\x1b[34m")
+			_, _ = buffer.WriteString(diff.Text)
+			_, _ = buffer.WriteString("\x1b[0m")
+		case DELETE:
+			_, _ = buffer.WriteString("\x1b[31m")
+			_, _ = buffer.WriteString(diff.Text)
+			_, _ = buffer.WriteString("\x1b[0m")
+		case EQUAL:
+			_, _ = buffer.WriteString(diff.Text)
+		}
+	}
+	return buffer.String()
+}
+
+// Convert a diff array into plain text where every line is prefixed with
+// "+", "-", " " or ">" for moved text. Text not ending in a newline is
+// terminated with one, so the output reads best for line-mode diffs.
+func DiffPrettyTextNoColor(diffs []Diff) string {
+	var buffer bytes.Buffer
+	for _, diff := range diffs {
+		var prefix string
+		switch diff.Type {
+		case INSERT:
+			prefix = "+"
+		case MOVE:
+			prefix = ">"
+		case DELETE:
+			prefix = "-"
+		case EQUAL:
+			prefix = " "
+		}
+		text := diff.Text
+		for len(text) > 0 {
+			line := text
+			if i := strings.IndexByte(text, '\n'); i != -1 {
+				line = text[:i+1]
+			}
+			text = text[len(line):]
+			_, _ = buffer.WriteString(prefix)
+			_, _ = buffer.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				_ = buffer.WriteByte('\n')
+			}
+		}
+	}
+	return buffer.String()
+}
@@ -0,0 +1,15 @@
+package diff
+
+import "context"
+
+// DiffDistance returns the Levenshtein distance between textA and textB, in
+// runes, as DiffLevenshtein counts it on their diff.  It runs the same half
+// match and bisect core as DiffMain, including Diff_Timeout, and the
+// merging of DiffCleanupMerge, which factors out and shifts text the edits
+// have in common, but skips the line mode speedup and every other cleanup.
+func (dmp *DiffMatchPatch) DiffDistance(textA, textB string) int {
+	ctx, cancel := dmp.timeoutContext(context.Background())
+	defer cancel()
+
+	return dmp.DiffLevenshtein(dmp.diffMainContext(ctx, []rune(textA), []rune(textB), false))
+}
@@ -0,0 +1,89 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDistance(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+
+		Expected int
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", 0},
+		{"Equality", "abc", "abc", 0},
+		{"Insertion", "abc", "ab123c", 3},
+		{"Deletion", "a123bc", "abc", 3},
+		{"Substitution", "abc", "axc", 1},
+		{"Nothing in common", "abc", "xyz", 3},
+		{"Inside the longer text", "xabcy", "abc", 2},
+		{"Half match", "1234567890abcdefghij", "a345678z90abcdefghij", 3},
+		{"Bisection", "cat", "map", 2},
+		{"Runes", "日本語のテキスト", "日本のテキスト。", 2},
+	} {
+		actual := dmp.DiffDistance(tc.TextA, tc.TextB)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+
+		_, diffs := dmp.DiffMain([]rune(tc.TextA), []rune(tc.TextB), false)
+		assert.Equal(t, dmp.DiffLevenshtein(diffs), actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Random texts of few distinct runes, which DiffCleanupMerge has to
+	// factor and shift a lot.
+	r := rand.New(rand.NewSource(1))
+	randomText := func() string {
+		runes := make([]rune, r.Intn(41))
+		for i := range runes {
+			runes[i] = []rune("abcxy12 ")[r.Intn(8)]
+		}
+		return string(runes)
+	}
+	for i := 0; i < 2000; i++ {
+		textA, textB := randomText(), randomText()
+		_, diffs := dmp.DiffMain([]rune(textA), []rune(textB), false)
+		assert.Equal(t, dmp.DiffLevenshtein(diffs), dmp.DiffDistance(textA, textB), fmt.Sprintf("DiffDistance(%q, %q)", textA, textB))
+	}
+	for _, tc := range [][2]string{{"ycc2caxx 2y", "xa11axx1 1 "}, {"aa1bx ca2yaba2a1yac2b", "xbcaccc ccaya"}} {
+		_, diffs := dmp.DiffMain([]rune(tc[0]), []rune(tc[1]), false)
+		assert.Equal(t, dmp.DiffLevenshtein(diffs), dmp.DiffDistance(tc[0], tc[1]), fmt.Sprintf("DiffDistance(%q, %q)", tc[0], tc[1]))
+	}
+
+	dmp.Diff_Timeout = 0
+	for n := 100; n < 5000; n += 100 {
+		textA, textB := randomEditedText(n, n/20)
+		_, diffs := dmp.DiffMain(textA, textB, false)
+		actual := dmp.DiffDistance(string(textA), string(textB))
+		assert.Equal(t, dmp.DiffLevenshtein(diffs), actual, fmt.Sprintf("Length %d", n))
+	}
+}
+
+func BenchmarkDiffDistance(b *testing.B) {
+	runesA, runesB := randomEditedText(20000, 200)
+	textA, textB := string(runesA), string(runesB)
+	dmp := New()
+	dmp.Diff_Timeout = 0
+
+	b.Run("Distance", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmp.DiffDistance(textA, textB)
+		}
+	})
+	b.Run("Levenshtein", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dmp.DiffLevenshtein(dmp.DiffMainStrings(textA, textB, false))
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"context"
+	"hash/maphash"
+	"strings"
+)
+
+// DiffLineModeHashed diffs textA and textB line by line, like DiffReaders,
+// for very large texts with few repeated lines.  Instead of a table of the
+// unique lines, it keeps a 64-bit hash and the offsets of one instance of
+// each, comparing lines with the same hash by their text to rule out
+// collisions.  The texts of the diffs are substrings of textA and textB, cut
+// out by offset, so beyond the inputs the diff holds little more than one
+// rune per line.  The diff is not refined below line granularity.
+func (dmp *DiffMatchPatch) DiffLineModeHashed(textA, textB string) []Diff {
+	lines := newLineHasher(textA, textB, maphash.MakeSeed())
+	charsA := lines.munge(0)
+	charsB := lines.munge(1)
+
+	ctx, cancel := dmp.timeoutContext(context.Background())
+	defer cancel()
+
+	diffs := dmp.diffMainContext(ctx, charsA, charsB, false)
+
+	// Convert the diff back to the original text.
+	diffsWithText := make([]Diff, 0, len(diffs))
+	posA, posB := 0, 0
+	for _, aDiff := range diffs {
+		length := 0
+		for _, r := range aDiff.Text {
+			length += lines.length(runeToInt(r))
+		}
+		switch aDiff.Type {
+		case EQUAL:
+			aDiff.Text = textA[posA : posA+length]
+			posA += length
+			posB += length
+		case DELETE:
+			aDiff.Text = textA[posA : posA+length]
+			posA += length
+		case INSERT:
+			aDiff.Text = textB[posB : posB+length]
+			posB += length
+		}
+		diffsWithText = append(diffsWithText, aDiff)
+	}
+	return diffsWithText
+}
+
+// Where one instance of a unique line is: the text it is in and its byte
+// offsets.
+type lineSpan struct {
+	text       int
+	start, end int
+}
+
+// Numbers the unique lines of two texts by their hash, like the lineHash and
+// lineArray of DiffLinesToChars, but without holding on to their text.
+type lineHasher struct {
+	texts [2]string
+	hash  func(string) uint64
+	// The unique lines, index 0 is reserved like lineArray's junk entry.
+	lines []lineSpan
+	// The first line with each hash, and after each line the next one with
+	// the same hash, 0 for none.
+	first map[uint64]uint32
+	next  []uint32
+}
+
+func newLineHasher(textA, textB string, seed maphash.Seed) *lineHasher {
+	return &lineHasher{
+		texts: [2]string{textA, textB},
+		hash:  func(line string) uint64 { return maphash.String(seed, line) },
+		lines: []lineSpan{{}},
+		first: make(map[uint64]uint32),
+		next:  []uint32{0},
+	}
+}
+
+// The length in bytes of the line numbered i.
+func (h *lineHasher) length(i uint32) int {
+	return h.lines[i].end - h.lines[i].start
+}
+
+// Split texts[text] into lines and return one rune per line as encoded by
+// intToRune, numbering unseen lines as it goes.
+func (h *lineHasher) munge(text int) []rune {
+	s := h.texts[text]
+	chars := []rune{}
+	lineStart := 0
+
+	for lineStart < len(s) {
+		lineEnd := lineStart + strings.IndexByte(s[lineStart:], '\n') + 1
+		if lineEnd == lineStart || len(h.lines) >= MAX_RUNE_INDEX-1 {
+			// No newline left, or out of runes: the rest of the text becomes
+			// the last line, as in diffLinesMunge.
+			lineEnd = len(s)
+		}
+		chars = append(chars, intToRune(h.lookup(text, lineStart, lineEnd)))
+		lineStart = lineEnd
+	}
+	return chars
+}
+
+// The number of the line at texts[text][start:end], adding it if unseen.
+func (h *lineHasher) lookup(text, start, end int) uint32 {
+	line := h.texts[text][start:end]
+	hash := h.hash(line)
+	i := h.first[hash]
+	for ; i != 0; i = h.next[i] {
+		span := h.lines[i]
+		if h.texts[span.text][span.start:span.end] == line {
+			return i
+		}
+	}
+
+	// An unseen line, put it at the head of the chain for its hash.
+	i = uint32(len(h.lines))
+	h.lines = append(h.lines, lineSpan{text, start, end})
+	h.next = append(h.next, h.first[hash])
+	h.first[hash] = i
+	return i
+}
@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"fmt"
+	"hash/maphash"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLineModeHashed(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+
+		Expected []Diff
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", []Diff{}},
+		{"Equal", "alpha\nbeta\n", "alpha\nbeta\n", []Diff{{EQUAL, "alpha\nbeta\n"}}},
+		{"Insert lines", "", "alpha\nbeta\n", []Diff{{INSERT, "alpha\nbeta\n"}}},
+		{"Delete a line", "alpha\nbeta\ngamma\n", "alpha\ngamma\n", []Diff{{EQUAL, "alpha\n"}, {DELETE, "beta\n"}, {EQUAL, "gamma\n"}}},
+		{"Replace a line", "alpha\nbeta\ngamma\n", "alpha\nbeta 2\ngamma\n", []Diff{{EQUAL, "alpha\n"}, {DELETE, "beta\n"}, {INSERT, "beta 2\n"}, {EQUAL, "gamma\n"}}},
+		{"Omit final newline", "alpha\n", "alpha\nbeta", []Diff{{EQUAL, "alpha\n"}, {INSERT, "beta"}}},
+		{"Runes", "日本\n語\n", "日本\nテキスト\n", []Diff{{EQUAL, "日本\n"}, {DELETE, "語\n"}, {INSERT, "テキスト\n"}}},
+	} {
+		actual := dmp.DiffLineModeHashed(tc.TextA, tc.TextB)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Long inputs diff as DiffReaders diffs them.
+	var a, b strings.Builder
+	for x := 0; x < 10000; x++ {
+		_, _ = fmt.Fprintf(&a, "line %d\n", x%100)
+		if x%1000 != 0 {
+			_, _ = fmt.Fprintf(&b, "line %d\n", x%100)
+		}
+		if x%700 == 0 {
+			_, _ = fmt.Fprintf(&b, "new line %d\n", x)
+		}
+	}
+	expected, err := dmp.DiffReaders(strings.NewReader(a.String()), strings.NewReader(b.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, dmp.DiffLineModeHashed(a.String(), b.String()))
+}
+
+func TestLineHasherCollisions(t *testing.T) {
+	// Every line has the same hash, they are told apart by their text.
+	lines := newLineHasher("a\nb\na\n", "b\nc\n", maphash.MakeSeed())
+	lines.hash = func(string) uint64 { return 1 }
+
+	assert.Equal(t, []rune{1, 2, 1}, lines.munge(0))
+	assert.Equal(t, []rune{2, 3}, lines.munge(1))
+	assert.Equal(t, []lineSpan{{}, {0, 0, 2}, {0, 2, 4}, {1, 2, 4}}, lines.lines)
+}
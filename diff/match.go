@@ -0,0 +1,265 @@
+package diff
+
+import (
+	"math"
+	"strconv"
+)
+
+// * match_main
+// Locate the best instance of pattern in text near loc, -1 if none.
+// loc and the returned location count runes.  The fuzzy search handles
+// patterns of up to Match_MaxBits runes, longer ones are only found where
+// they occur exactly.  Split a long pattern into chunks to match it
+// fuzzily, the way PatchApply matches the ends of a long patch.
+func (dmp *DiffMatchPatch) MatchMain(text, pattern string, loc int) int {
+	return dmp.matchMain([]rune(text), []rune(pattern), loc)
+}
+
+// MatchMainScore is MatchMain which also returns the score of the match,
+// from 0.0 for an exact match at loc up to Match_Threshold.  Errors and the
+// distance from loc both add to the score, see Match_Distance.  Without a
+// match the location is -1 and the score +Inf.
+func (dmp *DiffMatchPatch) MatchMainScore(text, pattern string, loc int) (int, float64) {
+	return dmp.matchMainScore([]rune(text), []rune(pattern), loc)
+}
+
+// Rune based match_main, shared with patch application.
+func (dmp *DiffMatchPatch) matchMain(text, pattern []rune, loc int) int {
+	bestLoc, _ := dmp.matchMainScore(text, pattern, loc)
+	return bestLoc
+}
+
+// MatchAny locates the best instance of any of patterns in text near loc,
+// and returns the index of that pattern and its location, or -1 and -1 if
+// none matches.  The best match is the one MatchMainScore scores lowest, on
+// a tie the first pattern wins.  The text is decoded once, and each pattern
+// only searches for matches better than the best so far, which prunes most
+// of the bitap work for later patterns.
+func (dmp *DiffMatchPatch) MatchAny(text string, patterns []string, loc int) (int, int) {
+	runes := []rune(text)
+	bestPattern, bestLoc := -1, -1
+	bestScore := float64(dmp.Match_Threshold)
+	for i, pattern := range patterns {
+		matchLoc, score := dmp.matchMainBelow(runes, []rune(pattern), loc, bestScore)
+		if matchLoc != -1 && (bestPattern == -1 || score < bestScore) {
+			bestPattern, bestLoc, bestScore = i, matchLoc, score
+		}
+	}
+	return bestPattern, bestLoc
+}
+
+func (dmp *DiffMatchPatch) matchMainScore(text, pattern []rune, loc int) (int, float64) {
+	return dmp.matchMainBelow(text, pattern, loc, float64(dmp.Match_Threshold))
+}
+
+// matchMainScore which only looks for fuzzy matches scoring threshold or
+// better.
+func (dmp *DiffMatchPatch) matchMainBelow(text, pattern []rune, loc int, threshold float64) (int, float64) {
+	loc = max(0, min(loc, len(text)))
+	if runesEqual(text, pattern) {
+		// Shortcut (potentially not guaranteed by the algorithm)
+		if len(pattern) == 0 {
+			return 0, 0
+		}
+		return 0, dmp.matchBitapScore(0, 0, loc, pattern)
+	} else if len(text) == 0 {
+		// Nothing to match.
+		return -1, math.Inf(1)
+	} else if loc+len(pattern) <= len(text) && runesEqual(text[loc:loc+len(pattern)], pattern) {
+		// Perfect match at the perfect spot!  (Includes case of null pattern)
+		return loc, 0
+	}
+	// Do a fuzzy compare.
+	return dmp.matchBitapBelow(text, pattern, loc, threshold)
+}
+
+// * match_bitap_
+// Locate the best instance of pattern in text near loc using the Bitap
+// algorithm, -1 if none.  Like MatchMain, a pattern longer than
+// Match_MaxBits runes is only found exactly.
+func (dmp *DiffMatchPatch) MatchBitap(text, pattern string, loc int) int {
+	runes := []rune(text)
+	bestLoc, _ := dmp.matchBitap(runes, []rune(pattern), max(0, min(loc, len(runes))))
+	return bestLoc
+}
+
+// Rune based match_bitap_, which also returns the score of the best match,
+// +Inf if there is none.
+func (dmp *DiffMatchPatch) matchBitap(text, pattern []rune, loc int) (int, float64) {
+	return dmp.matchBitapBelow(text, pattern, loc, float64(dmp.Match_Threshold))
+}
+
+// matchBitap giving up on matches which score worse than scoreThreshold.
+func (dmp *DiffMatchPatch) matchBitapBelow(text, pattern []rune, loc int, scoreThreshold float64) (int, float64) {
+	// Highest score beyond which we give up.
+	// Is there a nearby exact match? (speedup)
+	exactLoc, exactScore := -1, math.Inf(1)
+	bestLoc := runesIndexOf(text, pattern, loc)
+	if bestLoc != -1 {
+		if score := dmp.matchBitapScore(0, bestLoc, loc, pattern); score <= scoreThreshold {
+			exactLoc, exactScore = bestLoc, score
+			scoreThreshold = score
+		}
+		// What about in the other direction? (speedup)
+		bestLoc = runesLastIndexOf(text, pattern, loc+len(pattern))
+		if bestLoc != -1 {
+			if score := dmp.matchBitapScore(0, bestLoc, loc, pattern); score < scoreThreshold {
+				exactLoc, exactScore = bestLoc, score
+				scoreThreshold = score
+			}
+		}
+	}
+	if len(pattern) > dmp.matchMaxPatternLen() {
+		// The bit arrays can't hold the pattern, an exact match is all
+		// there is.
+		return exactLoc, exactScore
+	}
+
+	// Initialise the alphabet.
+	s := dmp.matchAlphabet(pattern)
+
+	// Initialise the bit arrays.
+	matchmask := 1 << uint(len(pattern)-1)
+	bestLoc = -1
+
+	var binMin, binMid int
+	binMax := len(pattern) + len(text)
+	lastRd := []int{}
+	for d := 0; d < len(pattern); d++ {
+		// Scan for the best match; each iteration allows for one more error.
+		// Run a binary search to determine how far from 'loc' we can stray at
+		// this error level.
+		binMin = 0
+		binMid = binMax
+		for binMin < binMid {
+			if dmp.matchBitapScore(d, loc+binMid, loc, pattern) <= scoreThreshold {
+				binMin = binMid
+			} else {
+				binMax = binMid
+			}
+			binMid = (binMax-binMin)/2 + binMin
+		}
+		// Use the result from this iteration as the maximum for the next.
+		binMax = binMid
+		start := max(1, loc-binMid+1)
+		finish := min(loc+binMid, len(text)) + len(pattern)
+
+		rd := make([]int, finish+2)
+		rd[finish+1] = (1 << uint(d)) - 1
+
+		for j := finish; j >= start; j-- {
+			var charMatch int
+			if j-1 < len(text) {
+				charMatch = s[text[j-1]]
+			}
+
+			if d == 0 {
+				// First pass: exact match.
+				rd[j] = ((rd[j+1] << 1) | 1) & charMatch
+			} else {
+				// Subsequent passes: fuzzy match.
+				rd[j] = ((rd[j+1]<<1)|1)&charMatch | (((lastRd[j+1] | lastRd[j]) << 1) | 1) | lastRd[j+1]
+			}
+			if (rd[j] & matchmask) != 0 {
+				score := dmp.matchBitapScore(d, j-1, loc, pattern)
+				// This match will almost certainly be better than any existing
+				// match.  But check anyway.
+				if score <= scoreThreshold {
+					// Told you so.
+					scoreThreshold = score
+					bestLoc = j - 1
+					if bestLoc > loc {
+						// When passing loc, don't exceed our current distance from loc.
+						start = max(1, 2*loc-bestLoc)
+					} else {
+						// Already passed loc, downhill from here on in.
+						break
+					}
+				}
+			}
+		}
+		if dmp.matchBitapScore(d+1, loc, loc, pattern) > scoreThreshold {
+			// No hope for a (better) match at greater error levels.
+			break
+		}
+		lastRd = rd
+	}
+	if bestLoc == -1 {
+		return -1, math.Inf(1)
+	}
+	// The threshold drops to the score of every better match found.
+	return bestLoc, scoreThreshold
+}
+
+// The longest pattern the bitap can search: Match_MaxBits runes, but no
+// more than the bits of an int.
+func (dmp *DiffMatchPatch) matchMaxPatternLen() int {
+	if dmp.Match_MaxBits == 0 || int(dmp.Match_MaxBits) > strconv.IntSize {
+		return strconv.IntSize
+	}
+	return int(dmp.Match_MaxBits)
+}
+
+// * match_bitapScore_
+// Compute the score for a match with e errors and x location.
+func (dmp *DiffMatchPatch) matchBitapScore(e, x, loc int, pattern []rune) float64 {
+	accuracy := float64(e) / float64(len(pattern))
+	proximity := math.Abs(float64(loc - x))
+	if dmp.Match_Distance == 0 {
+		// Dodge divide by zero error.
+		if proximity == 0 {
+			return accuracy
+		}
+		return 1.0
+	}
+	return accuracy + (proximity / float64(dmp.Match_Distance))
+}
+
+// * match_alphabet_
+// Initialise the alphabet for the Bitap algorithm.
+func (dmp *DiffMatchPatch) MatchAlphabet(pattern string) map[rune]int {
+	return dmp.matchAlphabet([]rune(pattern))
+}
+
+func (dmp *DiffMatchPatch) matchAlphabet(pattern []rune) map[rune]int {
+	s := map[rune]int{}
+	for i, char := range pattern {
+		s[char] |= 1 << uint(len(pattern)-i-1)
+	}
+	return s
+}
+
+// runesIndexOf returns the index of the first instance of pattern in target
+// at or after start, -1 if none.
+func runesIndexOf(target, pattern []rune, start int) int {
+	start = max(0, start)
+	for i := start; i+len(pattern) <= len(target); i++ {
+		if runesEqual(target[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+// runesLastIndexOf returns the index of the last instance of pattern in
+// target starting at or before start, -1 if none.
+func runesLastIndexOf(target, pattern []rune, start int) int {
+	for i := min(start, len(target)-len(pattern)); i >= 0; i-- {
+		if runesEqual(target[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+func runesEqual(r1, r2 []rune) bool {
+	if len(r1) != len(r2) {
+		return false
+	}
+	for i, c := range r1 {
+		if c != r2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,321 @@
+package diff
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchAlphabet(t *testing.T) {
+	type TestCase struct {
+		Pattern string
+
+		Expected map[rune]int
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{
+			Pattern: "abc",
+
+			Expected: map[rune]int{
+				'a': 4,
+				'b': 2,
+				'c': 1,
+			},
+		},
+		{
+			Pattern: "abcaba",
+
+			Expected: map[rune]int{
+				'a': 37,
+				'b': 18,
+				'c': 8,
+			},
+		},
+		{
+			Pattern: "日本日",
+
+			Expected: map[rune]int{
+				'日': 5,
+				'本': 2,
+			},
+		},
+	} {
+		actual := dmp.MatchAlphabet(tc.Pattern)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %#v", i, tc))
+	}
+}
+
+func TestMatchBitap(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text     string
+		Pattern  string
+		Location int
+
+		Expected int
+	}
+
+	dmp := New()
+	dmp.Match_Distance = 100
+	dmp.Match_Threshold = 0.5
+
+	for i, tc := range []TestCase{
+		{"Exact match #1", "abcdefghijk", "fgh", 5, 5},
+		{"Exact match #2", "abcdefghijk", "fgh", 0, 5},
+		{"Fuzzy match #1", "abcdefghijk", "efxhi", 0, 4},
+		{"Fuzzy match #2", "abcdefghijk", "cdefxyhijk", 5, 2},
+		{"Fuzzy match #3", "abcdefghijk", "bxy", 1, -1},
+		{"Overflow", "123456789xx0", "3456789x0", 2, 2},
+		{"Before start match", "abcdef", "xxabc", 4, 0},
+		{"Beyond end match", "abcdef", "defyy", 4, 3},
+		{"Oversized pattern", "abcdef", "xabcdefy", 0, 0},
+		{"Rune offsets", "ééabcdef", "cdxf", 0, 4},
+		{"Location before start", "abcdef", "abc", -100, 0},
+		{"Location before start, no match", "abcdef", "xy", -100, -1},
+		{"Location past end", "abcdef", "def", 100, 3},
+		{"Location past end, no match", "abcdef", "xy", 100, -1},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	dmp.Match_Threshold = 0.4
+
+	for i, tc := range []TestCase{
+		{"Threshold #1", "abcdefghijk", "efxyhi", 1, 4},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	dmp.Match_Threshold = 0.3
+
+	for i, tc := range []TestCase{
+		{"Threshold #2", "abcdefghijk", "efxyhi", 1, -1},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	dmp.Match_Threshold = 0.0
+
+	for i, tc := range []TestCase{
+		{"Threshold #3", "abcdefghijk", "bcdef", 1, 1},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	dmp.Match_Threshold = 0.5
+
+	for i, tc := range []TestCase{
+		{"Multiple select #1", "abcdexyzabcde", "abccde", 3, 0},
+		{"Multiple select #2", "abcdexyzabcde", "abccde", 5, 8},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Strict location.
+	dmp.Match_Distance = 10
+
+	for i, tc := range []TestCase{
+		{"Distance test #1", "abcdefghijklmnopqrstuvwxyz", "abcdefg", 24, -1},
+		{"Distance test #2", "abcdefghijklmnopqrstuvwxyz", "abcdxxefg", 1, 0},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Loose location.
+	dmp.Match_Distance = 1000
+
+	for i, tc := range []TestCase{
+		{"Distance test #3", "abcdefghijklmnopqrstuvwxyz", "abcdefg", 24, 0},
+	} {
+		actual := dmp.MatchBitap(tc.Text, tc.Pattern, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestMatchMain(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text1    string
+		Text2    string
+		Location int
+
+		Expected int
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Equality", "abcdef", "abcdef", 1000, 0},
+		{"Null text", "", "abcdef", 1, -1},
+		{"Null pattern", "abcdef", "", 3, 3},
+		{"Exact match", "abcdef", "de", 3, 3},
+		{"Beyond end match", "abcdef", "defy", 4, 3},
+		{"Oversized pattern", "abcdef", "abcdefy", 0, 0},
+		{"Rune offsets", "日本語のテキスト", "テキスト", 2, 4},
+	} {
+		actual := dmp.MatchMain(tc.Text1, tc.Text2, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	dmp.Match_Threshold = 0.7
+
+	for i, tc := range []TestCase{
+		{"Complex match", "I am the very model of a modern major general.", " that berry ", 5, 4},
+	} {
+		actual := dmp.MatchMain(tc.Text1, tc.Text2, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestMatchMainMaxBits(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Pattern string
+
+		Expected int
+	}
+
+	dmp := New()
+	dmp.Match_Distance = 1000
+	dmp.Match_Threshold = 0.5
+	text := "xxxxx" + strings.Repeat("abcdefghij", 4) + "xxxxx"
+
+	for i, tc := range []TestCase{
+		{"Fuzzy at the limit", "abcdefghij" + "abcdeXghij" + "abcdefghij" + "ab", 5},
+		{"Exact at the limit", strings.Repeat("abcdefghij", 3) + "ab", 5},
+		{"Fuzzy beyond the limit", "abcdefghij" + "abcdeXghij" + "abcdefghij" + "abc", -1},
+		{"Exact beyond the limit", strings.Repeat("abcdefghij", 3) + "abc", 5},
+	} {
+		actual := dmp.MatchMain(text, tc.Pattern, 5)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		actual = dmp.MatchBitap(text, tc.Pattern, 5)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// An exact match beyond the limit still scores by its distance from loc.
+	loc, score := dmp.MatchMainScore(text, strings.Repeat("abcdefghij", 4), 0)
+	assert.Equal(t, 5, loc)
+	assert.Equal(t, 0.005, score)
+
+	// The bits of an int are the limit whatever Match_MaxBits says.
+	dmp.Match_MaxBits = 1000
+	long := strings.Repeat("abcdefghij", 7)
+	assert.Equal(t, -1, dmp.MatchMain(strings.Replace(long, "e", "E", 1), long, 0))
+	assert.Equal(t, 5, dmp.MatchMain("xxxxx"+long, long, 0))
+}
+
+func TestMatchMainScore(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text1    string
+		Text2    string
+		Location int
+
+		Expected      int
+		ExpectedScore float64
+	}
+
+	dmp := New()
+	dmp.Match_Distance = 100
+
+	for i, tc := range []TestCase{
+		{"Equality", "abcdef", "abcdef", 0, 0, 0},
+		{"Equality away from loc", "abcdef", "abcdef", 5, 0, 0.05},
+		{"Null text", "", "abcdef", 1, -1, math.Inf(1)},
+		{"Null pattern", "abcdef", "", 3, 3, 0},
+		{"Exact match", "abcdef", "de", 3, 3, 0},
+		{"Exact match away from loc", "abcdefghij", "hi", 2, 7, 0.05},
+		{"One error", "abcdefghij", "dxf", 3, 3, 1.0 / 3},
+		{"No match", "abcdef", "xyz", 3, -1, math.Inf(1)},
+	} {
+		actual, actualScore := dmp.MatchMainScore(tc.Text1, tc.Text2, tc.Location)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.InDelta(t, tc.ExpectedScore, actualScore, 1e-9, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		// The location agrees with MatchMain.
+		assert.Equal(t, dmp.MatchMain(tc.Text1, tc.Text2, tc.Location), actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text     string
+		Patterns []string
+		Location int
+
+		ExpectedPattern  int
+		ExpectedLocation int
+	}
+
+	dmp := New()
+	dmp.Match_Distance = 100
+
+	for i, tc := range []TestCase{
+		{"No patterns", "abcdef", nil, 0, -1, -1},
+		{"Null text", "", []string{"abc"}, 0, -1, -1},
+		{"No match", "abcdef", []string{"xyz", "uvw"}, 3, -1, -1},
+		{"One pattern", "abcdefghij", []string{"hi"}, 2, 0, 7},
+		{"Exact beats fuzzy", "abcdefghij", []string{"dxf", "ghi"}, 3, 1, 6},
+		{"Nearer beats further", "abcdefghijabc", []string{"ij", "bc"}, 0, 1, 1},
+		{"First wins a tie", "abcabc", []string{"bc", "ab", "bc"}, 3, 1, 3},
+		{"Runes", "日本語のテキスト", []string{"テキス", "のテ"}, 3, 1, 3},
+	} {
+		actualPattern, actualLocation := dmp.MatchAny(tc.Text, tc.Patterns, tc.Location)
+		assert.Equal(t, tc.ExpectedPattern, actualPattern, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedLocation, actualLocation, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// The best pattern is the one MatchMainScore scores lowest.
+	text := "The quick brown fox jumps over the lazy dog"
+	patterns := []string{"lazy cat", "quack", "brwn fx", "jumps"}
+	pattern, loc := dmp.MatchAny(text, patterns, 15)
+	bestLoc, bestScore := dmp.MatchMainScore(text, patterns[pattern], 15)
+	assert.Equal(t, bestLoc, loc)
+	for _, other := range patterns {
+		_, score := dmp.MatchMainScore(text, other, 15)
+		assert.LessOrEqual(t, bestScore, score, other)
+	}
+}
+
+func BenchmarkMatchAny(b *testing.B) {
+	runes, _ := randomEditedText(5000, 0)
+	text := string(runes)
+	var patterns []string
+	for i := 0; i < 20; i++ {
+		patterns = append(patterns, text[i*200+7:i*200+27])
+	}
+	dmp := New()
+
+	b.Run("Any", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dmp.MatchAny(text, patterns, 2500)
+		}
+	})
+	b.Run("Serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bestScore := math.Inf(1)
+			for _, pattern := range patterns {
+				if _, score := dmp.MatchMainScore(text, pattern, 2500); score < bestScore {
+					bestScore = score
+				}
+			}
+		}
+	})
+}
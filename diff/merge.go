@@ -0,0 +1,176 @@
+package diff
+
+import "strings"
+
+// A Conflict is a stretch of base which mine and theirs both changed, each
+// in its own way.  Pos is its rune offset in base, Base the original text of
+// the stretch and Mine and Theirs what each side made of it.  A conflict
+// covers whole lines of base, unless a merged edit ends on its first line.
+type Conflict struct {
+	Pos    int
+	Base   string
+	Mine   string
+	Theirs string
+}
+
+// The markers Merge3 puts around a conflict.
+const (
+	mergeMarkerMine   = "<<<<<<< mine\n"
+	mergeMarkerSplit  = "=======\n"
+	mergeMarkerTheirs = ">>>>>>> theirs\n"
+)
+
+// Merge3 merges the changes from base to mine and from base to theirs, like
+// diff3.  Edits which only one side made, or which both made alike, are
+// applied.  Where both sides changed the same part of base differently, the
+// lines concerned are returned as a Conflict, and the merged text holds both
+// versions between the classic <<<<<<< mine, ======= and >>>>>>> theirs
+// markers.  Without conflicts the merged text is ready to use.
+func (dmp *DiffMatchPatch) Merge3(base, mine, theirs string) (string, []Conflict) {
+	editsMine := dmp.mergeEdits(base, mine)
+	editsTheirs := dmp.mergeEdits(base, theirs)
+	baseRunes := []rune(base)
+
+	var merged strings.Builder
+	var conflicts []Conflict
+	pos := 0 // Runes of base up to here are in merged.
+	for len(editsMine) > 0 || len(editsTheirs) > 0 {
+		// Start with the edit which comes first, insertions before the
+		// deletions at the same place.
+		var start, end int
+		if len(editsTheirs) == 0 || len(editsMine) > 0 && editBefore(editsMine[0], editsTheirs[0]) {
+			start, end = editsMine[0].Pos, editsMine[0].Pos+editsMine[0].Delete
+		} else {
+			start, end = editsTheirs[0].Pos, editsTheirs[0].Pos+editsTheirs[0].Delete
+		}
+
+		var nMine, nTheirs int
+		conflict := false
+		for {
+			// Take in all edits of either side which overlap the stretch,
+			// until it doesn't grow any more.
+			for {
+				prevStart, prevEnd := start, end
+				nMine, start, end = overlappingEdits(editsMine, start, end)
+				nTheirs, start, end = overlappingEdits(editsTheirs, start, end)
+				if start == prevStart && end == prevEnd {
+					break
+				}
+			}
+			if nMine == 0 || nTheirs == 0 {
+				break
+			}
+			textMine := applyEdits(baseRunes, editsMine[:nMine], start, end)
+			textTheirs := applyEdits(baseRunes, editsTheirs[:nTheirs], start, end)
+			if textMine == textTheirs && !conflict {
+				break
+			}
+			// A conflict widens to whole lines, but what is merged already
+			// stays as it is.
+			conflict = true
+			lineStart, lineEnd := lineBounds(baseRunes, start, end)
+			lineStart = max(lineStart, pos)
+			if lineStart == start && lineEnd == end {
+				break
+			}
+			start, end = lineStart, lineEnd
+		}
+
+		_, _ = merged.WriteString(string(baseRunes[pos:start]))
+		textMine := applyEdits(baseRunes, editsMine[:nMine], start, end)
+		if conflict {
+			textTheirs := applyEdits(baseRunes, editsTheirs[:nTheirs], start, end)
+			conflicts = append(conflicts, Conflict{
+				Pos:    start,
+				Base:   string(baseRunes[start:end]),
+				Mine:   textMine,
+				Theirs: textTheirs,
+			})
+			_, _ = merged.WriteString(mergeMarkerMine)
+			_, _ = merged.WriteString(endLine(textMine))
+			_, _ = merged.WriteString(mergeMarkerSplit)
+			_, _ = merged.WriteString(endLine(textTheirs))
+			_, _ = merged.WriteString(mergeMarkerTheirs)
+		} else if nMine > 0 {
+			_, _ = merged.WriteString(textMine)
+		} else {
+			_, _ = merged.WriteString(applyEdits(baseRunes, editsTheirs[:nTheirs], start, end))
+		}
+		pos = end
+		editsMine = editsMine[nMine:]
+		editsTheirs = editsTheirs[nTheirs:]
+	}
+	_, _ = merged.WriteString(string(baseRunes[pos:]))
+	return merged.String(), conflicts
+}
+
+// The edits from base to text, cleaned up so that they follow the meaning
+// of the text rather than chance matches.
+func (dmp *DiffMatchPatch) mergeEdits(base, text string) []Edit {
+	diffs := dmp.DiffMainStrings(base, text, true)
+	return DiffEditScript(dmp.DiffCleanupSemantic(diffs))
+}
+
+// Whether edit a goes before edit b at the same place.
+func editBefore(a, b Edit) bool {
+	if a.Pos != b.Pos {
+		return a.Pos < b.Pos
+	}
+	return a.Delete < b.Delete
+}
+
+// Count the edits at the head of edits which overlap the stretch of base
+// from start to end, and widen the stretch to cover them.  Every edit which
+// starts inside the stretch overlaps it, and so does an insertion at its
+// start.  Edits before start are merged already.
+func overlappingEdits(edits []Edit, start, end int) (int, int, int) {
+	n := 0
+	for _, edit := range edits {
+		editEnd := edit.Pos + edit.Delete
+		if edit.Pos >= end && !(edit.Pos == start && edit.Delete == 0) {
+			break
+		}
+		start = min(start, edit.Pos)
+		end = max(end, editEnd)
+		n++
+	}
+	return n, start, end
+}
+
+// Apply edits, which lie within the stretch of base from start to end, to
+// that stretch.
+func applyEdits(base []rune, edits []Edit, start, end int) string {
+	var text strings.Builder
+	pointer := start
+	for _, edit := range edits {
+		_, _ = text.WriteString(string(base[pointer:edit.Pos]))
+		_, _ = text.WriteString(edit.Insert)
+		pointer = edit.Pos + edit.Delete
+	}
+	_, _ = text.WriteString(string(base[pointer:end]))
+	return text.String()
+}
+
+// Widen the stretch of text from start to end to whole lines.  A stretch
+// which is empty at the start of a line stays so.
+func lineBounds(text []rune, start, end int) (int, int) {
+	for start > 0 && text[start-1] != '\n' {
+		start--
+	}
+	if end == start {
+		return start, end
+	}
+	for end < len(text) && text[end-1] != '\n' {
+		end++
+	}
+	return start, end
+}
+
+// text with a line break at the end, so a conflict marker after it starts a
+// line of its own.
+func endLine(text string) string {
+	if len(text) == 0 || strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Base   string
+		Mine   string
+		Theirs string
+
+		Expected          string
+		ExpectedConflicts []Conflict
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", "", "", nil},
+		{"No changes", "abc", "abc", "abc", "abc", nil},
+		{"Only mine", "a\nb\nc\n", "a\nB\nc\n", "a\nb\nc\n", "a\nB\nc\n", nil},
+		{"Only theirs", "a\nb\nc\n", "a\nb\nc\n", "a\nb\nC\n", "a\nb\nC\n", nil},
+		{"Separate lines", "a\nb\nc\n", "a\nB\nc\n", "a\nb\nC\n", "a\nB\nC\n", nil},
+		{"Same line, separate words", "the cat sat on the mat", "the dog sat on the mat", "the cat sat on the rug", "the dog sat on the rug", nil},
+		{"Same change", "a\nb\nc\n", "a\nX\nc\n", "a\nX\nc\n", "a\nX\nc\n", nil},
+		{"Deletion and insertion", "a\n", "", "a\nb\n", "b\n", nil},
+		{
+			"Conflicting lines", "a\nb\nc\n", "a\nX\nc\n", "a\nY\nc\n",
+			"a\n<<<<<<< mine\nX\n=======\nY\n>>>>>>> theirs\nc\n",
+			[]Conflict{{Pos: 2, Base: "b\n", Mine: "X\n", Theirs: "Y\n"}},
+		},
+		{
+			"Conflict widened to the line", "the cat sat", "the dog sat", "the cow sat",
+			"<<<<<<< mine\nthe dog sat\n=======\nthe cow sat\n>>>>>>> theirs\n",
+			[]Conflict{{Pos: 0, Base: "the cat sat", Mine: "the dog sat", Theirs: "the cow sat"}},
+		},
+		{
+			"Insertions at the same place", "", "x", "y",
+			"<<<<<<< mine\nx\n=======\ny\n>>>>>>> theirs\n",
+			[]Conflict{{Pos: 0, Base: "", Mine: "x", Theirs: "y"}},
+		},
+		{
+			"Conflict cutting through a later edit", "\nzyy", "zy", "",
+			"<<<<<<< mine\nzy\n=======\n>>>>>>> theirs\n",
+			[]Conflict{{Pos: 0, Base: "\nzyy", Mine: "zy", Theirs: ""}},
+		},
+		{
+			"Runes", "日本\n語\n", "日本\nご\n", "日本\n話\n",
+			"日本\n<<<<<<< mine\nご\n=======\n話\n>>>>>>> theirs\n",
+			[]Conflict{{Pos: 3, Base: "語\n", Mine: "ご\n", Theirs: "話\n"}},
+		},
+	} {
+		actual, conflicts := dmp.Merge3(tc.Base, tc.Mine, tc.Theirs)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedConflicts, conflicts, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Random merges of small texts with many lines.
+	r := rand.New(rand.NewSource(1))
+	randomText := func() string {
+		runes := make([]rune, r.Intn(12))
+		for i := range runes {
+			runes[i] = []rune("xyz\n")[r.Intn(4)]
+		}
+		return string(runes)
+	}
+	for i := 0; i < 3000; i++ {
+		base, mine, theirs := randomText(), randomText(), randomText()
+		name := fmt.Sprintf("Merge3(%q, %q, %q)", base, mine, theirs)
+		actual, conflicts := dmp.Merge3(base, mine, theirs)
+		if len(conflicts) == 0 {
+			assert.NotContains(t, actual, mergeMarkerMine, name)
+		}
+		baseRunes := []rune(base)
+		for _, c := range conflicts {
+			assert.Equal(t, c.Base, string(baseRunes[c.Pos:c.Pos+utf8.RuneCountInString(c.Base)]), name)
+			assert.Contains(t, actual, mergeMarkerMine+endLine(c.Mine)+mergeMarkerSplit+endLine(c.Theirs)+mergeMarkerTheirs, name)
+		}
+		actual, conflicts = dmp.Merge3(base, mine, base)
+		assert.Equal(t, mine, actual, name)
+		assert.Empty(t, conflicts, name)
+	}
+
+	// Changes on one side only merge to that side.
+	runesA, runesB := randomEditedText(2000, 100)
+	base, mine := string(runesA), string(runesB)
+	actual, conflicts := dmp.Merge3(base, mine, base)
+	assert.Equal(t, mine, actual)
+	assert.Empty(t, conflicts)
+	actual, conflicts = dmp.Merge3(base, base, mine)
+	assert.Equal(t, mine, actual)
+	assert.Empty(t, conflicts)
+	actual, conflicts = dmp.Merge3(base, mine, mine)
+	assert.Equal(t, mine, actual)
+	assert.Empty(t, conflicts)
+}
@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// DiffOptions selects differences which DiffMainOpts should not report.
+type DiffOptions struct {
+	// IgnoreCase compares runes by their lower case form.
+	IgnoreCase bool
+	// IgnoreWhitespace leaves whitespace runes out of the comparison.
+	IgnoreWhitespace bool
+}
+
+// Diff two strings, ignoring the differences selected by opts.
+// The diff is computed on normalized copies of the texts but carries the
+// original text, so it still shows the real content.  Insertions and
+// equalities are taken from textB, deletions from textA: DiffTextResult
+// returns textB, while DiffTextSource only matches textA up to the ignored
+// differences.
+func (dmp *DiffMatchPatch) DiffMainOpts(textA, textB string, opts DiffOptions) []Diff {
+	runesA := []rune(textA)
+	runesB := []rune(textB)
+	shadowA, indexA := normalizeRunes(runesA, opts)
+	shadowB, indexB := normalizeRunes(runesB, opts)
+
+	shadowDiffs := dmp.DiffMainRunes(shadowA, shadowB)
+
+	// Map each diff back onto the original texts.
+	diffs := make([]Diff, 0, len(shadowDiffs)+1)
+	pointerA, pointerB := 0, 0 // Offsets in the normalized texts.
+	for _, aDiff := range shadowDiffs {
+		n := utf8.RuneCountInString(aDiff.Text)
+		var text []rune
+		switch aDiff.Type {
+		case DELETE:
+			text = runesA[shadowBound(indexA, pointerA, len(runesA)):shadowBound(indexA, pointerA+n, len(runesA))]
+			pointerA += n
+		case INSERT:
+			text = runesB[shadowBound(indexB, pointerB, len(runesB)):shadowBound(indexB, pointerB+n, len(runesB))]
+			pointerB += n
+		case EQUAL:
+			// Both sides are equal up to the ignored differences, show textB.
+			text = runesB[shadowBound(indexB, pointerB, len(runesB)):shadowBound(indexB, pointerB+n, len(runesB))]
+			pointerA += n
+			pointerB += n
+		}
+		diffs = append(diffs, Diff{aDiff.Type, string(text)})
+	}
+	if len(shadowB) == 0 && len(runesB) != 0 {
+		// textB is nothing but ignored whitespace, which counts as unchanged.
+		diffs = append(diffs, Diff{EQUAL, textB})
+	}
+	return diffs
+}
+
+// Build the normalized copy of text which the diff is computed on, along
+// with the offset in text of each of its runes.
+func normalizeRunes(text []rune, opts DiffOptions) ([]rune, []int) {
+	shadow := make([]rune, 0, len(text))
+	index := make([]int, 0, len(text))
+	for i, r := range text {
+		if opts.IgnoreWhitespace && unicode.IsSpace(r) {
+			continue
+		}
+		if opts.IgnoreCase {
+			r = unicode.ToLower(r)
+		}
+		shadow = append(shadow, r)
+		index = append(index, i)
+	}
+	return shadow, index
+}
+
+// Offset in the original text (of the given length) at which normalized rune
+// k starts.  Skipped runes belong to the normalized rune after them, or to the
+// last one at the end of the text.
+func shadowBound(index []int, k, length int) int {
+	switch k {
+	case 0:
+		return 0
+	case len(index):
+		return length
+	default:
+		return index[k-1] + 1
+	}
+}
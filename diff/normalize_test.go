@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMainOpts(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+		Opts  DiffOptions
+
+		Expected []Diff
+	}
+
+	dmp := New()
+
+	ignoreCase := DiffOptions{IgnoreCase: true}
+	ignoreWhitespace := DiffOptions{IgnoreWhitespace: true}
+	ignoreBoth := DiffOptions{IgnoreCase: true, IgnoreWhitespace: true}
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", ignoreBoth, []Diff{}},
+		{"No options", "Foo bar", "foo baz", DiffOptions{}, []Diff{{DELETE, "F"}, {INSERT, "f"}, {EQUAL, "oo ba"}, {DELETE, "r"}, {INSERT, "z"}}},
+		{"Case only", "Hello World", "hello world", ignoreCase, []Diff{{EQUAL, "hello world"}}},
+		{"Case and an edit", "Foo bar", "foo baz", ignoreCase, []Diff{{EQUAL, "foo ba"}, {DELETE, "r"}, {INSERT, "z"}}},
+		{"Case of multi-byte runes", "ÄÖÜ", "äöü", ignoreCase, []Diff{{EQUAL, "äöü"}}},
+		{"Case is not whitespace", "a b", "A  B", ignoreCase, []Diff{{EQUAL, "A "}, {INSERT, " "}, {EQUAL, "B"}}},
+		{"Whitespace only", "key = value  ", "key=value", ignoreWhitespace, []Diff{{EQUAL, "key=value"}}},
+		{"Whitespace added", "key=value", "key = value\t", ignoreWhitespace, []Diff{{EQUAL, "key = value\t"}}},
+		{"Whitespace and an edit", "a b c", "a  b d", ignoreWhitespace, []Diff{{EQUAL, "a  b"}, {DELETE, " c"}, {INSERT, " d"}}},
+		{"Whitespace is not case", "key=value", "Key=value", ignoreWhitespace, []Diff{{DELETE, "k"}, {INSERT, "K"}, {EQUAL, "ey=value"}}},
+		{"Case and whitespace", "Name: Foo", "name:foo", ignoreBoth, []Diff{{EQUAL, "name:foo"}}},
+		{"All whitespace", "x", " \n", ignoreWhitespace, []Diff{{DELETE, "x"}, {EQUAL, " \n"}}},
+		{"All whitespace removed", " \n", "x", ignoreWhitespace, []Diff{{INSERT, "x"}}},
+	} {
+		actual := dmp.DiffMainOpts(tc.TextA, tc.TextB, tc.Opts)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.TextB, dmp.DiffTextResult(actual), fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	DELETE Operation = iota + 1
+	INSERT
+	EQUAL
+	// MOVE is an insertion of text which is deleted elsewhere in the same
+	// diff, see DiffDetectMoves.
+	MOVE
+)
+
+type Operation int
+
+func (op Operation) String() string {
+	if op < DELETE || op > MOVE {
+		// E.g. the zero Diff, which fmt may well print.
+		return "Operation(" + strconv.Itoa(int(op)) + ")"
+	}
+	return [...]string{"DELETE", "INSERT", "EQUAL", "MOVE"}[op-1]
+}
+
+func (op Operation) EnumIndex() int {
+	return int(op)
+}
+
+// IsEdit reports whether op changes the text: true for DELETE and INSERT,
+// and for MOVE, which is an insertion.  The zero Operation is none of them.
+func (op Operation) IsEdit() bool {
+	return op == DELETE || op == INSERT || op == MOVE
+}
+
+// MarshalJSON encodes op by name, e.g. "INSERT".
+func (op Operation) MarshalJSON() ([]byte, error) {
+	if op < DELETE || op > MOVE {
+		return nil, fmt.Errorf("invalid operation: %d", int(op))
+	}
+	return json.Marshal(op.String())
+}
+
+// UnmarshalJSON decodes an operation encoded by MarshalJSON.
+func (op *Operation) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("operation must be a string: %w", err)
+	}
+	for candidate := DELETE; candidate <= MOVE; candidate++ {
+		if candidate.String() == name {
+			*op = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid operation: %q", name)
+}
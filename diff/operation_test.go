@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationJSON(t *testing.T) {
+	type TestCase struct {
+		Op Operation
+
+		Expected string
+	}
+
+	for i, tc := range []TestCase{
+		{DELETE, `"DELETE"`},
+		{INSERT, `"INSERT"`},
+		{EQUAL, `"EQUAL"`},
+		{MOVE, `"MOVE"`},
+	} {
+		data, err := json.Marshal(tc.Op)
+		assert.NoError(t, err, fmt.Sprintf("Test case #%d, %#v", i, tc))
+		assert.Equal(t, tc.Expected, string(data), fmt.Sprintf("Test case #%d, %#v", i, tc))
+
+		var actual Operation
+		assert.NoError(t, json.Unmarshal(data, &actual), fmt.Sprintf("Test case #%d, %#v", i, tc))
+		assert.Equal(t, tc.Op, actual, fmt.Sprintf("Test case #%d, %#v", i, tc))
+	}
+
+	data, err := json.Marshal(Diff{INSERT, "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Type":"INSERT","Text":"abc"}`, string(data))
+
+	_, err = json.Marshal(Operation(0))
+	assert.Error(t, err)
+
+	var op Operation
+	for i, data := range []string{`"REPLACE"`, `"insert"`, `2`, `null`} {
+		assert.Error(t, json.Unmarshal([]byte(data), &op), fmt.Sprintf("Test case #%d, %s", i, data))
+	}
+}
+
+func TestOperationIsEdit(t *testing.T) {
+	assert.True(t, DELETE.IsEdit())
+	assert.True(t, INSERT.IsEdit())
+	assert.False(t, EQUAL.IsEdit())
+	assert.True(t, MOVE.IsEdit())
+	assert.False(t, Operation(0).IsEdit())
+	assert.False(t, Operation(9).IsEdit())
+}
+
+func TestOperationString(t *testing.T) {
+	assert.Equal(t, "DELETE", DELETE.String())
+	assert.Equal(t, "MOVE", MOVE.String())
+	assert.Equal(t, "Operation(0)", Operation(0).String())
+	assert.Equal(t, "Operation(9)", Operation(9).String())
+}
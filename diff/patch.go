@@ -0,0 +1,640 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Patch represents one hunk of a patch. Offsets and lengths count runes.
+type Patch struct {
+	Diffs   []Diff
+	Start1  int
+	Start2  int
+	Length1 int
+	Length2 int
+	// SourceLine is the 1-based line of the source text a hunk parsed by
+	// PatchFromUnifiedDiff starts at, whose rune offsets aren't known until
+	// PatchApply sees the text.  0 for other patches.
+	SourceLine int
+}
+
+// * patch_make
+// Compute a list of patches to turn text1 into text2, given the diffs
+// between them.
+func (dmp *DiffMatchPatch) PatchMake(text1 string, diffs []Diff) []Patch {
+	patches := []Patch{}
+	if len(diffs) == 0 {
+		return patches // Get rid of the null case.
+	}
+
+	patch := Patch{}
+	charCount1 := 0 // Number of characters into the text1 string.
+	charCount2 := 0 // Number of characters into the text2 string.
+	// Start with text1 (prepatchText) and apply the diffs until we arrive at
+	// text2 (postpatchText). We recreate the patches one by one to determine
+	// context info.
+	prepatchText := []rune(text1)
+	postpatchText := []rune(text1)
+	for i, aDiff := range diffs {
+		diffText := []rune(aDiff.Text)
+		if len(patch.Diffs) == 0 && aDiff.Type != EQUAL {
+			// A new patch starts here.
+			patch.Start1 = charCount1
+			patch.Start2 = charCount2
+		}
+
+		switch aDiff.Type {
+		case INSERT:
+			patch.Diffs = append(patch.Diffs, aDiff)
+			patch.Length2 += len(diffText)
+			postpatchText = concatRunes(postpatchText[:charCount2], diffText, postpatchText[charCount2:])
+		case DELETE:
+			patch.Length1 += len(diffText)
+			patch.Diffs = append(patch.Diffs, aDiff)
+			postpatchText = concatRunes(postpatchText[:charCount2], postpatchText[charCount2+len(diffText):])
+		case EQUAL:
+			if len(diffText) <= 2*int(dmp.Patch_Margin) && len(patch.Diffs) != 0 && i != len(diffs)-1 {
+				// Small equality inside a patch.
+				patch.Diffs = append(patch.Diffs, aDiff)
+				patch.Length1 += len(diffText)
+				patch.Length2 += len(diffText)
+			} else if len(diffText) >= 2*int(dmp.Patch_Margin) {
+				// Time for a new patch.
+				if len(patch.Diffs) != 0 {
+					patch = dmp.patchAddContext(patch, prepatchText)
+					patches = append(patches, patch)
+					patch = Patch{}
+					// Unlike Unidiff, our patch lists have a rolling context.
+					// http://code.google.com/p/google-diff-match-patch/wiki/Unidiff
+					// Update prepatch text & pos to reflect the application of the
+					// just completed patch.
+					prepatchText = postpatchText
+					charCount1 = charCount2
+				}
+			}
+		}
+
+		// Update the current character count.
+		if aDiff.Type != INSERT {
+			charCount1 += len(diffText)
+		}
+		if aDiff.Type != DELETE {
+			charCount2 += len(diffText)
+		}
+	}
+
+	// Pick up the leftover patch if not empty.
+	if len(patch.Diffs) != 0 {
+		patch = dmp.patchAddContext(patch, prepatchText)
+		patches = append(patches, patch)
+	}
+
+	return patches
+}
+
+// * patch_addContext_
+// Increase the context until it is unique, but don't let the pattern expand
+// beyond Match_MaxBits.  text is the source text the patch applies to.
+func (dmp *DiffMatchPatch) PatchAddContext(patch Patch, text string) Patch {
+	return dmp.patchAddContext(patch, []rune(text))
+}
+
+// Rune based patch_addContext_, shared with PatchMake.
+func (dmp *DiffMatchPatch) patchAddContext(patch Patch, text []rune) Patch {
+	if len(text) == 0 {
+		return patch
+	}
+
+	pattern := text[patch.Start2:min(len(text), patch.Start2+patch.Length1)]
+	padding := 0
+
+	// Look for the first and last matches of pattern in text.  If two
+	// different matches are found, increase the pattern length.  Without a
+	// Patch_Margin it can't grow.
+	for dmp.Patch_Margin > 0 &&
+		runesIndexOf(text, pattern, 0) != runesLastIndexOf(text, pattern, len(text)) &&
+		len(pattern) < dmp.matchMaxPatternLen()-2*int(dmp.Patch_Margin) {
+		padding += int(dmp.Patch_Margin)
+		maxStart := max(0, patch.Start2-padding)
+		minEnd := min(len(text), patch.Start2+patch.Length1+padding)
+		pattern = text[maxStart:minEnd]
+	}
+	// Add one chunk for good luck.
+	padding += int(dmp.Patch_Margin)
+
+	// Add the prefix.
+	prefix := text[max(0, patch.Start2-padding):patch.Start2]
+	if len(prefix) != 0 {
+		patch.Diffs = append([]Diff{{EQUAL, string(prefix)}}, patch.Diffs...)
+	}
+	// Add the suffix.
+	suffix := text[min(len(text), patch.Start2+patch.Length1):min(len(text), patch.Start2+patch.Length1+padding)]
+	if len(suffix) != 0 {
+		// Cap the slice so the caller's diffs are never written to.
+		patch.Diffs = append(patch.Diffs[:len(patch.Diffs):len(patch.Diffs)], Diff{EQUAL, string(suffix)})
+	}
+
+	// Roll back the start points.
+	patch.Start1 -= len(prefix)
+	patch.Start2 -= len(prefix)
+	// Extend the lengths.
+	patch.Length1 += len(prefix) + len(suffix)
+	patch.Length2 += len(prefix) + len(suffix)
+
+	return patch
+}
+
+// * patch_apply
+// Merge a set of patches onto the text. Returns the patched text and a
+// slice of booleans indicating which patches were applied.
+func (dmp *DiffMatchPatch) PatchApply(patches []Patch, text string) (string, []bool) {
+	if len(patches) == 0 {
+		return text, []bool{}
+	}
+
+	// Deep copy the patches so that no changes are made to originals.
+	patches = dmp.PatchDeepCopy(patches)
+	patchResolveLines(patches, text)
+
+	nullPadding := []rune(dmp.PatchAddPadding(patches))
+	textRunes := concatRunes(nullPadding, []rune(text), nullPadding)
+	patches = dmp.PatchSplitMax(patches)
+
+	maxBits := dmp.matchMaxPatternLen()
+	// delta keeps track of the offset between the expected and actual
+	// location of the previous patch.  If there are patches expected at
+	// positions 10 and 20, but the first patch was found at 12, delta is 2
+	// and the second patch has an effective expected position of 22.
+	delta := 0
+	results := make([]bool, len(patches))
+	for x, aPatch := range patches {
+		expectedLoc := aPatch.Start2 + delta
+		text1 := []rune(dmp.DiffTextSource(aPatch.Diffs))
+		var startLoc int
+		endLoc := -1
+		if len(text1) > maxBits {
+			// PatchSplitMax will only provide an oversized pattern in the case
+			// of a monster delete.
+			startLoc = dmp.matchMain(textRunes, text1[:maxBits], expectedLoc)
+			if startLoc != -1 {
+				endLoc = dmp.matchMain(textRunes, text1[len(text1)-maxBits:], expectedLoc+len(text1)-maxBits)
+				if endLoc == -1 || startLoc >= endLoc {
+					// Can't find valid trailing context.  Drop this patch.
+					startLoc = -1
+				}
+			}
+		} else {
+			startLoc = dmp.matchMain(textRunes, text1, expectedLoc)
+		}
+		if startLoc == -1 {
+			// No match found.  :(
+			results[x] = false
+			// Subtract the delta for this failed patch from subsequent patches.
+			delta -= aPatch.Length2 - aPatch.Length1
+			continue
+		}
+
+		// Found a match.  :)
+		results[x] = true
+		delta = startLoc - expectedLoc
+		var text2 []rune
+		if endLoc == -1 {
+			text2 = textRunes[startLoc:min(startLoc+len(text1), len(textRunes))]
+		} else {
+			text2 = textRunes[startLoc:min(endLoc+maxBits, len(textRunes))]
+		}
+		if runesEqual(text1, text2) {
+			// Perfect match, just shove the Replacement text in.
+			textRunes = concatRunes(textRunes[:startLoc], []rune(dmp.DiffTextResult(aPatch.Diffs)), textRunes[startLoc+len(text1):])
+			continue
+		}
+
+		// Imperfect match.  Run a diff to get a framework of equivalent
+		// indices.
+		_, diffs := dmp.DiffMain(text1, text2, false)
+		if len(text1) > maxBits && float64(dmp.DiffLevenshtein(diffs))/float64(len(text1)) > float64(dmp.Patch_DeleteThreshold) {
+			// The end points match, but the content is unacceptably bad.
+			results[x] = false
+			continue
+		}
+		diffs = dmp.DiffCleanupSemanticLossless(diffs)
+		index1 := 0
+		for _, aDiff := range aPatch.Diffs {
+			diffText := []rune(aDiff.Text)
+			if aDiff.Type != EQUAL {
+				index2 := dmp.DiffXIndex(diffs, index1)
+				if aDiff.Type == INSERT {
+					// Insertion
+					textRunes = concatRunes(textRunes[:startLoc+index2], diffText, textRunes[startLoc+index2:])
+				} else if aDiff.Type == DELETE {
+					// Deletion
+					endIndex := min(len(textRunes), startLoc+dmp.DiffXIndex(diffs, index1+len(diffText)))
+					textRunes = concatRunes(textRunes[:startLoc+index2], textRunes[endIndex:])
+				}
+			}
+			if aDiff.Type != DELETE {
+				index1 += len(diffText)
+			}
+		}
+	}
+	// Strip the padding off.
+	textRunes = textRunes[len(nullPadding) : len(textRunes)-len(nullPadding)]
+	return string(textRunes), results
+}
+
+// Work out Start1 and Start2 of the patches from a unified diff, which only
+// know their line in text.  The patches are changed in place.
+func patchResolveLines(patches []Patch, text string) {
+	var lineStarts []int
+	textLength := 0
+	delta := 0 // Offset between the source and the patched text.
+	for x, aPatch := range patches {
+		if aPatch.SourceLine > 0 {
+			if lineStarts == nil {
+				lineStarts = []int{0}
+				for _, r := range text {
+					textLength++
+					if r == '\n' {
+						lineStarts = append(lineStarts, textLength)
+					}
+				}
+			}
+			if aPatch.SourceLine <= len(lineStarts) {
+				patches[x].Start1 = lineStarts[aPatch.SourceLine-1]
+			} else {
+				// Past the end, the text is shorter than the diff expects.
+				patches[x].Start1 = textLength
+			}
+			patches[x].Start2 = patches[x].Start1 + delta
+		}
+		delta += aPatch.Length2 - aPatch.Length1
+	}
+}
+
+// * patch_deepCopy
+// Given an array of patches, return another array that is identical.  The
+// copies have their own Diffs, so they can be changed without touching the
+// originals.
+func (dmp *DiffMatchPatch) PatchDeepCopy(patches []Patch) []Patch {
+	patchesCopy := make([]Patch, 0, len(patches))
+	for _, aPatch := range patches {
+		patchCopy := aPatch
+		patchCopy.Diffs = make([]Diff, len(aPatch.Diffs))
+		copy(patchCopy.Diffs, aPatch.Diffs)
+		patchesCopy = append(patchesCopy, patchCopy)
+	}
+	return patchesCopy
+}
+
+// * patch_addPadding
+// Add some padding on text start and end so that edges can match something.
+// The patches are changed in place, and the returned padding (runes 1 to
+// Patch_Margin) must be added to both ends of the text they are applied to.
+func (dmp *DiffMatchPatch) PatchAddPadding(patches []Patch) string {
+	paddingLength := int(dmp.Patch_Margin)
+	nullPadding := make([]rune, 0, paddingLength)
+	for x := 1; x <= paddingLength; x++ {
+		nullPadding = append(nullPadding, rune(x))
+	}
+	if len(patches) == 0 {
+		return string(nullPadding)
+	}
+
+	// Bump all the patches forward.
+	for i := range patches {
+		patches[i].Start1 += paddingLength
+		patches[i].Start2 += paddingLength
+	}
+
+	// Add some padding on start of first diff.
+	first := &patches[0]
+	if len(first.Diffs) == 0 || first.Diffs[0].Type != EQUAL {
+		// Add nullPadding equality.
+		first.Diffs = append([]Diff{{EQUAL, string(nullPadding)}}, first.Diffs...)
+		first.Start1 -= paddingLength // Should be 0.
+		first.Start2 -= paddingLength // Should be 0.
+		first.Length1 += paddingLength
+		first.Length2 += paddingLength
+	} else if firstText := []rune(first.Diffs[0].Text); paddingLength > len(firstText) {
+		// Grow first equality.
+		extraLength := paddingLength - len(firstText)
+		first.Diffs[0].Text = string(nullPadding[len(firstText):]) + first.Diffs[0].Text
+		first.Start1 -= extraLength
+		first.Start2 -= extraLength
+		first.Length1 += extraLength
+		first.Length2 += extraLength
+	}
+
+	// Add some padding on end of last diff.
+	last := &patches[len(patches)-1]
+	if len(last.Diffs) == 0 || last.Diffs[len(last.Diffs)-1].Type != EQUAL {
+		// Add nullPadding equality.
+		last.Diffs = append(last.Diffs, Diff{EQUAL, string(nullPadding)})
+		last.Length1 += paddingLength
+		last.Length2 += paddingLength
+	} else if lastText := []rune(last.Diffs[len(last.Diffs)-1].Text); paddingLength > len(lastText) {
+		// Grow last equality.
+		extraLength := paddingLength - len(lastText)
+		last.Diffs[len(last.Diffs)-1].Text += string(nullPadding[:extraLength])
+		last.Length1 += extraLength
+		last.Length2 += extraLength
+	}
+
+	return string(nullPadding)
+}
+
+// * patch_splitMax
+// Look through the patches and break up any which are longer than the
+// maximum limit of the match algorithm.  Each piece carries Patch_Margin
+// context from its neighbours.  The given patches are not modified.
+func (dmp *DiffMatchPatch) PatchSplitMax(patches []Patch) []Patch {
+	patchSize := dmp.matchMaxPatternLen()
+	// The context on both sides must leave room for at least one rune of
+	// the patch, or the pieces never get through it.
+	patchMargin := min(int(dmp.Patch_Margin), (patchSize-1)/2)
+	patches = append([]Patch(nil), patches...)
+	for x := 0; x < len(patches); x++ {
+		if patches[x].Length1 <= patchSize {
+			continue
+		}
+		bigpatch := patches[x]
+		bigpatch.Diffs = append([]Diff(nil), bigpatch.Diffs...)
+		// Remove the big old patch.
+		patches = append(patches[:x], patches[x+1:]...)
+		x--
+
+		start1 := bigpatch.Start1
+		start2 := bigpatch.Start2
+		precontext := []rune{}
+		for len(bigpatch.Diffs) != 0 {
+			// Create one of several smaller patches.
+			patch := Patch{}
+			empty := true
+			patch.Start1 = start1 - len(precontext)
+			patch.Start2 = start2 - len(precontext)
+			if len(precontext) != 0 {
+				patch.Length1 = len(precontext)
+				patch.Length2 = len(precontext)
+				patch.Diffs = append(patch.Diffs, Diff{EQUAL, string(precontext)})
+			}
+			for len(bigpatch.Diffs) != 0 && patch.Length1 < patchSize-patchMargin {
+				diffType := bigpatch.Diffs[0].Type
+				diffText := []rune(bigpatch.Diffs[0].Text)
+				if diffType == INSERT {
+					// Insertions are harmless.
+					patch.Length2 += len(diffText)
+					start2 += len(diffText)
+					patch.Diffs = append(patch.Diffs, bigpatch.Diffs[0])
+					bigpatch.Diffs = bigpatch.Diffs[1:]
+					empty = false
+				} else if diffType == DELETE && len(patch.Diffs) == 1 && patch.Diffs[0].Type == EQUAL && len(diffText) > 2*patchSize {
+					// This is a large deletion.  Let it pass in one chunk.
+					patch.Length1 += len(diffText)
+					start1 += len(diffText)
+					empty = false
+					patch.Diffs = append(patch.Diffs, Diff{diffType, string(diffText)})
+					bigpatch.Diffs = bigpatch.Diffs[1:]
+				} else {
+					// Deletion or equality.  Only take as much as we can stomach.
+					diffText = diffText[:min(len(diffText), patchSize-patch.Length1-patchMargin)]
+
+					patch.Length1 += len(diffText)
+					start1 += len(diffText)
+					if diffType == EQUAL {
+						patch.Length2 += len(diffText)
+						start2 += len(diffText)
+					} else {
+						empty = false
+					}
+					patch.Diffs = append(patch.Diffs, Diff{diffType, string(diffText)})
+					if string(diffText) == bigpatch.Diffs[0].Text {
+						bigpatch.Diffs = bigpatch.Diffs[1:]
+					} else {
+						bigpatch.Diffs[0].Text = string([]rune(bigpatch.Diffs[0].Text)[len(diffText):])
+					}
+				}
+			}
+			// Compute the head context for the next patch.
+			precontext = []rune(dmp.DiffTextResult(patch.Diffs))
+			precontext = precontext[max(0, len(precontext)-patchMargin):]
+
+			// Append the end context for this patch.
+			postcontext := []rune(dmp.DiffTextSource(bigpatch.Diffs))
+			postcontext = postcontext[:min(len(postcontext), patchMargin)]
+			if len(postcontext) != 0 {
+				patch.Length1 += len(postcontext)
+				patch.Length2 += len(postcontext)
+				if len(patch.Diffs) != 0 && patch.Diffs[len(patch.Diffs)-1].Type == EQUAL {
+					patch.Diffs[len(patch.Diffs)-1].Text += string(postcontext)
+				} else {
+					patch.Diffs = append(patch.Diffs, Diff{EQUAL, string(postcontext)})
+				}
+			}
+			if !empty {
+				x++
+				patches = append(patches[:x], append([]Patch{patch}, patches[x:]...)...)
+			}
+		}
+	}
+	return patches
+}
+
+// Emulate GNU diff's format.
+// Header: @@ -382,8 +481,9 @@
+// Indices are printed as 1-based, not 0-based.
+func (patch *Patch) toText() string {
+	coords1 := unifiedCoords(patch.Start1, patch.Length1)
+	coords2 := unifiedCoords(patch.Start2, patch.Length2)
+
+	var text bytes.Buffer
+	_, _ = text.WriteString("@@ -" + coords1 + " +" + coords2 + " @@\n")
+
+	// Escape the body of the patch with %xx notation.
+	for _, aDiff := range patch.Diffs {
+		switch aDiff.Type {
+		case INSERT:
+			_, _ = text.WriteString("+")
+		case DELETE:
+			_, _ = text.WriteString("-")
+		case EQUAL:
+			_, _ = text.WriteString(" ")
+		}
+		_, _ = text.WriteString(encodeURI(aDiff.Text))
+		_, _ = text.WriteString("\n")
+	}
+
+	return text.String()
+}
+
+// String renders the patch for debugging: the hunk header, then each diff
+// on its own line as Diff.String shows it.  Use PatchToText for the patch
+// text format.
+func (patch Patch) String() string {
+	var text bytes.Buffer
+	_, _ = text.WriteString("@@ -" + unifiedCoords(patch.Start1, patch.Length1) + " +" + unifiedCoords(patch.Start2, patch.Length2) + " @@\n")
+	for _, aDiff := range patch.Diffs {
+		_, _ = text.WriteString("  " + aDiff.String() + "\n")
+	}
+	return text.String()
+}
+
+// * patch_toText
+// Take a list of patches and return a textual representation.
+func (dmp *DiffMatchPatch) PatchToText(patches []Patch) string {
+	var text bytes.Buffer
+	for _, aPatch := range patches {
+		_, _ = text.WriteString(aPatch.toText())
+	}
+	return text.String()
+}
+
+var patchHeaderRegex = regexp.MustCompile(`^@@ -(\d+),?(\d*) \+(\d+),?(\d*) @@$`)
+
+// * patch_fromText
+// Parse a textual representation of patches and return a list of Patch
+// objects.
+func (dmp *DiffMatchPatch) PatchFromText(textline string) ([]Patch, error) {
+	patches := []Patch{}
+	if len(textline) == 0 {
+		return patches, nil
+	}
+	text := strings.Split(textline, "\n")
+	textPointer := 0
+
+	for textPointer < len(text) {
+		m := patchHeaderRegex.FindStringSubmatch(text[textPointer])
+		if m == nil {
+			return patches, fmt.Errorf("invalid patch string: %q", text[textPointer])
+		}
+
+		patch := Patch{}
+		var err error
+		if patch.Start1, patch.Length1, err = parsePatchCoords(m[1], m[2]); err != nil {
+			return patches, fmt.Errorf("invalid patch string: %q: %w", text[textPointer], err)
+		}
+		if patch.Start2, patch.Length2, err = parsePatchCoords(m[3], m[4]); err != nil {
+			return patches, fmt.Errorf("invalid patch string: %q: %w", text[textPointer], err)
+		}
+		textPointer++
+
+		for textPointer < len(text) {
+			if len(text[textPointer]) == 0 {
+				// Blank line?  Whatever.
+				textPointer++
+				continue
+			}
+			sign := text[textPointer][0]
+			if sign == '@' {
+				// Start of next patch.
+				break
+			}
+			line, err := decodeURI(text[textPointer][1:])
+			if err != nil {
+				return patches, fmt.Errorf("illegal escape in patch: %q: %w", text[textPointer], err)
+			}
+
+			switch sign {
+			case '-':
+				// Deletion.
+				patch.Diffs = append(patch.Diffs, Diff{DELETE, line})
+			case '+':
+				// Insertion.
+				patch.Diffs = append(patch.Diffs, Diff{INSERT, line})
+			case ' ':
+				// Minor equality.
+				patch.Diffs = append(patch.Diffs, Diff{EQUAL, line})
+			default:
+				// WTF?
+				return patches, fmt.Errorf("invalid patch mode %q in: %q", string(sign), line)
+			}
+			textPointer++
+		}
+
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// MakePatchText diffs text1 against text2 and returns the patch text which
+// turns one into the other, the common DiffMain, DiffCleanupSemantic,
+// PatchMake and PatchToText pipeline in one call.
+func (dmp *DiffMatchPatch) MakePatchText(text1, text2 string) string {
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffString(text1, text2))
+	return dmp.PatchToText(dmp.PatchMake(text1, diffs))
+}
+
+// ApplyPatchText parses patchText, as MakePatchText or PatchToText write it,
+// and applies it to text with PatchApply.  The error is PatchFromText's, in
+// which case nothing is applied.
+func (dmp *DiffMatchPatch) ApplyPatchText(patchText, text string) (string, []bool, error) {
+	patches, err := dmp.PatchFromText(patchText)
+	if err != nil {
+		return text, nil, err
+	}
+	result, applied := dmp.PatchApply(patches, text)
+	return result, applied, nil
+}
+
+// parsePatchCoords converts the 1-based start and optional length of a patch
+// header back into a 0-based start and a length.  Only an empty stretch may
+// start at 0, which is before the first rune.
+func parsePatchCoords(startText, lengthText string) (int, int, error) {
+	// The header regex only matches digits, so Atoi can only fail on overflow.
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	length := 1
+	if lengthText != "" {
+		if length, err = strconv.Atoi(lengthText); err != nil {
+			return 0, 0, err
+		}
+	}
+	if length == 0 {
+		return start, 0, nil
+	} else if start == 0 {
+		return 0, 0, fmt.Errorf("stretch of %d runes starts at 0", length)
+	}
+	return start - 1, length, nil
+}
+
+// encodeURI escapes text the same way JavaScript's encodeURI does, except
+// that spaces are preserved, to stay compatible with the other ports.
+func encodeURI(text string) string {
+	const hex = "0123456789ABCDEF"
+	var buffer bytes.Buffer
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+			strings.IndexByte(" !#$&'()*+,-./:;=?@_~", c) != -1 {
+			_ = buffer.WriteByte(c)
+		} else {
+			_ = buffer.WriteByte('%')
+			_ = buffer.WriteByte(hex[c>>4])
+			_ = buffer.WriteByte(hex[c&15])
+		}
+	}
+	return buffer.String()
+}
+
+// decodeURI reverses encodeURI.
+func decodeURI(text string) (string, error) {
+	return url.PathUnescape(text)
+}
+
+// concatRunes joins the given rune slices into a newly allocated slice.
+func concatRunes(parts ...[]rune) []rune {
+	n := 0
+	for _, part := range parts {
+		n += len(part)
+	}
+	joined := make([]rune, 0, n)
+	for _, part := range parts {
+		joined = append(joined, part...)
+	}
+	return joined
+}
@@ -0,0 +1,547 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchMake(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text1 string
+		Diffs []Diff
+
+		Expected []Patch
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{
+			"Null case",
+			"",
+			[]Diff{},
+			[]Patch{},
+		},
+		{
+			"Single hunk",
+			"The quick brown fox jumps over the lazy dog.",
+			[]Diff{
+				{EQUAL, "The quick brown fox "},
+				{DELETE, "jumps"},
+				{INSERT, "leaps"},
+				{EQUAL, " over the lazy dog."},
+			},
+			[]Patch{
+				{
+					Diffs: []Diff{
+						{EQUAL, "fox "},
+						{DELETE, "jumps"},
+						{INSERT, "leaps"},
+						{EQUAL, " ove"},
+					},
+					Start1: 16, Start2: 16, Length1: 13, Length2: 13,
+				},
+			},
+		},
+		{
+			"Insert into empty text",
+			"",
+			[]Diff{{INSERT, "test"}},
+			[]Patch{
+				{
+					Diffs:  []Diff{{INSERT, "test"}},
+					Start1: 0, Start2: 0, Length1: 0, Length2: 4,
+				},
+			},
+		},
+		{
+			"Rune offsets",
+			"été chaud",
+			[]Diff{
+				{EQUAL, "été "},
+				{INSERT, "très "},
+				{EQUAL, "chaud"},
+			},
+			[]Patch{
+				{
+					Diffs: []Diff{
+						{EQUAL, "été "},
+						{INSERT, "très "},
+						{EQUAL, "chaud"},
+					},
+					Start1: 0, Start2: 0, Length1: 9, Length2: 14,
+				},
+			},
+		},
+	} {
+		actual := dmp.PatchMake(tc.Text1, tc.Diffs)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestPatchAddContext(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Patch string
+		Text  string
+
+		Expected string
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Simple case", "@@ -21,4 +21,10 @@\n-jump\n+somersault\n", "The quick brown fox jumps over the lazy dog.", "@@ -17,12 +17,18 @@\n fox \n-jump\n+somersault\n s ov\n"},
+		{"Not enough trailing context", "@@ -21,4 +21,10 @@\n-jump\n+somersault\n", "The quick brown fox jumps.", "@@ -17,10 +17,16 @@\n fox \n-jump\n+somersault\n s.\n"},
+		{"Not enough leading context", "@@ -3 +3,2 @@\n-e\n+at\n", "The quick brown fox jumps.", "@@ -1,7 +1,8 @@\n Th\n-e\n+at\n  qui\n"},
+		{"Ambiguity", "@@ -3 +3,2 @@\n-e\n+at\n", "The quick brown fox jumps.  The quick brown fox crashes.", "@@ -1,27 +1,28 @@\n Th\n-e\n+at\n  quick brown fox jumps. \n"},
+	} {
+		patches, err := dmp.PatchFromText(tc.Patch)
+		assert.NoError(t, err, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		actual := dmp.PatchAddContext(patches[0], tc.Text)
+		assert.Equal(t, tc.Expected, actual.toText(), fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		// The original patch is left alone.
+		assert.Equal(t, tc.Patch, patches[0].toText(), fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestPatchSplitMax(t *testing.T) {
+	dmp := New()
+	dmp.Match_MaxBits = 32
+
+	// A monster delete is cut into pieces sharing their edges as context.
+	patches := dmp.PatchMake("1234567890123456789012345678901234567890123456789012345678901234567890", []Diff{
+		{DELETE, "1234567890123456789012345678901234567890123456789012345678901234567890"},
+		{INSERT, "abc"},
+	})
+	original := dmp.PatchToText(patches)
+	actual := dmp.PatchSplitMax(patches)
+	assert.Equal(t, "@@ -1,32 +1,4 @@\n-1234567890123456789012345678\n 9012\n@@ -29,32 +1,4 @@\n-9012345678901234567890123456\n 7890\n@@ -57,14 +1,3 @@\n-78901234567890\n+abc\n", dmp.PatchToText(actual))
+	assert.Equal(t, original, dmp.PatchToText(patches))
+
+	// A long insertion needs no splitting, only the text it matches on counts.
+	text1 := "The quick brown fox jumps over the lazy dog."
+	insertion := strings.Repeat("very ", 100)
+	patches = dmp.PatchMake(text1, []Diff{
+		{EQUAL, "The quick brown fox jumps over the "},
+		{INSERT, insertion},
+		{EQUAL, "lazy dog."},
+	})
+	actual = dmp.PatchSplitMax(patches)
+	assert.Equal(t, patches, actual)
+	for _, patch := range actual {
+		assert.LessOrEqual(t, patch.Length1, int(dmp.Match_MaxBits))
+	}
+	result, applied := dmp.PatchApply(patches, text1)
+	assert.Equal(t, "The quick brown fox jumps over the "+insertion+"lazy dog.", result)
+	assert.Equal(t, []bool{true}, applied)
+
+	// A long replacement is split and still applies.
+	text1 = strings.Repeat("abcdefghij", 10)
+	patches = dmp.PatchMake(text1, []Diff{
+		{EQUAL, text1[:4]},
+		{DELETE, text1[4:50]},
+		{INSERT, insertion},
+		{EQUAL, text1[50:]},
+	})
+	actual = dmp.PatchSplitMax(patches)
+	assert.Greater(t, len(actual), 1)
+	for _, patch := range actual {
+		assert.LessOrEqual(t, patch.Length1, int(dmp.Match_MaxBits))
+	}
+	result, applied = dmp.PatchApply(patches, text1)
+	assert.Equal(t, text1[:4]+insertion+text1[50:], result)
+	assert.NotContains(t, applied, false)
+}
+
+func TestPatchMaxBitsZero(t *testing.T) {
+	text1 := strings.Repeat("abcdefghij", 10)
+	text2 := text1[:4] + strings.Repeat("very ", 20) + text1[50:]
+
+	// 0 means the bits of an int, for New with Match_MaxBits cleared and
+	// for the zero DiffMatchPatch alike.
+	cleared := New()
+	cleared.Match_MaxBits = 0
+	for i, dmp := range []*DiffMatchPatch{cleared, {}} {
+		patches := dmp.PatchMake(text1, dmp.DiffMainStrings(text1, text2, false))
+		for _, patch := range dmp.PatchSplitMax(patches) {
+			assert.LessOrEqual(t, patch.Length1, strconv.IntSize, fmt.Sprintf("Test case #%d", i))
+		}
+		result, applied := dmp.PatchApply(patches, text1)
+		assert.Equal(t, text2, result, fmt.Sprintf("Test case #%d", i))
+		assert.NotContains(t, applied, false, fmt.Sprintf("Test case #%d", i))
+	}
+}
+
+func TestPatchWideMargin(t *testing.T) {
+	text1 := strings.Repeat("abcdefghij", 10)
+	text2 := text1[:4] + strings.Repeat("very ", 20) + text1[50:]
+
+	// A margin of half the pattern length or more leaves no room for the
+	// patch itself.
+	small := New()
+	small.Match_MaxBits = 8
+	wide := New()
+	wide.Patch_Margin = 16
+	for i, dmp := range []*DiffMatchPatch{small, wide} {
+		patches := dmp.PatchMake(text1, dmp.DiffMainStrings(text1, text2, false))
+		result, applied := dmp.PatchApply(patches, text1)
+		assert.Equal(t, text2, result, fmt.Sprintf("Test case #%d", i))
+		assert.NotContains(t, applied, false, fmt.Sprintf("Test case #%d", i))
+	}
+}
+
+func TestPatchDeepCopy(t *testing.T) {
+	dmp := New()
+
+	patches, err := dmp.PatchFromText("@@ -21,18 +22,17 @@\n jump\n-s\n+ed\n  over \n-the\n+a\n %0Alaz\n")
+	assert.NoError(t, err)
+	expected := dmp.PatchToText(patches)
+
+	patchesCopy := dmp.PatchDeepCopy(patches)
+	assert.Equal(t, patches, patchesCopy)
+
+	patchesCopy[0].Start1 = 0
+	patchesCopy[0].Diffs[1].Text = "x"
+	patchesCopy[0].Diffs = append(patchesCopy[0].Diffs, Diff{INSERT, "y"})
+	assert.Equal(t, expected, dmp.PatchToText(patches))
+	assert.Equal(t, []Patch{}, dmp.PatchDeepCopy([]Patch{}))
+}
+
+func TestPatchAddPadding(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text1 string
+		Diffs []Diff
+
+		Expected       string
+		ExpectedPadded string
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{
+			"Both edges full",
+			"",
+			[]Diff{{INSERT, "test"}},
+			"@@ -0,0 +1,4 @@\n+test\n",
+			"@@ -1,8 +1,12 @@\n %01%02%03%04\n+test\n %01%02%03%04\n",
+		},
+		{
+			"Both edges partial",
+			"XY",
+			[]Diff{{EQUAL, "X"}, {INSERT, "test"}, {EQUAL, "Y"}},
+			"@@ -1,2 +1,6 @@\n X\n+test\n Y\n",
+			"@@ -2,8 +2,12 @@\n %02%03%04X\n+test\n Y%01%02%03\n",
+		},
+		{
+			"Both edges none",
+			"XXXXYYYY",
+			[]Diff{{EQUAL, "XXXX"}, {INSERT, "test"}, {EQUAL, "YYYY"}},
+			"@@ -1,8 +1,12 @@\n XXXX\n+test\n YYYY\n",
+			"@@ -5,8 +5,12 @@\n XXXX\n+test\n YYYY\n",
+		},
+	} {
+		patches := dmp.PatchMake(tc.Text1, tc.Diffs)
+		assert.Equal(t, tc.Expected, dmp.PatchToText(patches), fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		padding := dmp.PatchAddPadding(patches)
+		assert.Equal(t, "\x01\x02\x03\x04", padding, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedPadded, dmp.PatchToText(patches), fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	assert.Equal(t, "\x01\x02\x03\x04", dmp.PatchAddPadding([]Patch{}))
+
+	// Inserting at the start of an empty document needs the padding to match.
+	patches := dmp.PatchMake("", []Diff{{INSERT, "test"}})
+	actual, applied := dmp.PatchApply(patches, "")
+	assert.Equal(t, "test", actual)
+	assert.Equal(t, []bool{true}, applied)
+}
+
+func TestPatchApply(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text1 string
+		Diffs []Diff
+		Text  string
+
+		Expected        string
+		ExpectedApplied []bool
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{
+			"Null case",
+			"",
+			[]Diff{},
+			"Hello world.",
+			"Hello world.",
+			[]bool{},
+		},
+		{
+			"Exact match",
+			"The quick brown fox jumps over the lazy dog.",
+			[]Diff{
+				{EQUAL, "The quick brown fox "},
+				{DELETE, "jumps"},
+				{INSERT, "leaps"},
+				{EQUAL, " over the lazy dog."},
+			},
+			"The quick brown fox jumps over the lazy dog.",
+			"The quick brown fox leaps over the lazy dog.",
+			[]bool{true},
+		},
+		{
+			"Drifted offset",
+			"The quick brown fox jumps over the lazy dog.",
+			[]Diff{
+				{EQUAL, "The quick brown fox "},
+				{DELETE, "jumps"},
+				{INSERT, "leaps"},
+				{EQUAL, " over the lazy dog."},
+			},
+			"Once upon a time, the quick brown fox jumps over the lazy dog.",
+			"Once upon a time, the quick brown fox leaps over the lazy dog.",
+			[]bool{true},
+		},
+		{
+			"Multiple hunks",
+			"The quick brown fox jumps over the lazy dog.",
+			[]Diff{
+				{EQUAL, "The "},
+				{DELETE, "quick"},
+				{INSERT, "slow"},
+				{EQUAL, " brown fox jumps over the "},
+				{DELETE, "lazy"},
+				{INSERT, "sleepy"},
+				{EQUAL, " dog."},
+			},
+			"The quick brown fox jumps over the lazy dog.",
+			"The slow brown fox jumps over the sleepy dog.",
+			[]bool{true, true},
+		},
+		{
+			"No match",
+			"The quick brown fox jumps over the lazy dog.",
+			[]Diff{
+				{EQUAL, "The quick brown fox "},
+				{DELETE, "jumps"},
+				{INSERT, "leaps"},
+				{EQUAL, " over the lazy dog."},
+			},
+			"I am the very model of a modern major general.",
+			"I am the very model of a modern major general.",
+			[]bool{false},
+		},
+		{
+			"Edge exact match",
+			"",
+			[]Diff{{INSERT, "test"}},
+			"",
+			"test",
+			[]bool{true},
+		},
+		{
+			"Near edge exact match",
+			"XY",
+			[]Diff{{EQUAL, "X"}, {INSERT, "test"}, {EQUAL, "Y"}},
+			"XY",
+			"XtestY",
+			[]bool{true},
+		},
+		{
+			"Past end of text",
+			"abcdefghijklmnopqrstuvwxyz",
+			[]Diff{{EQUAL, "abcdefghijklmnopqrstuvw"}, {DELETE, "xyz"}, {INSERT, "XYZ"}},
+			"tuvwxyz",
+			"tuvwXYZ",
+			[]bool{true},
+		},
+	} {
+		patches := dmp.PatchMake(tc.Text1, tc.Diffs)
+		actual, actualApplied := dmp.PatchApply(patches, tc.Text)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedApplied, actualApplied, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestPatchApplyNoSideEffects(t *testing.T) {
+	dmp := New()
+
+	patches := dmp.PatchMake("The quick brown fox.", []Diff{
+		{EQUAL, "The "},
+		{DELETE, "quick"},
+		{INSERT, "slow"},
+		{EQUAL, " brown fox."},
+	})
+	expected := dmp.PatchMake("The quick brown fox.", []Diff{
+		{EQUAL, "The "},
+		{DELETE, "quick"},
+		{INSERT, "slow"},
+		{EQUAL, " brown fox."},
+	})
+
+	_, _ = dmp.PatchApply(patches, "The quick brown fox.")
+	assert.Equal(t, expected, patches)
+}
+
+func TestPatchFromText(t *testing.T) {
+	type TestCase struct {
+		Patch string
+
+		ErrorExpected bool
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"", false},
+		{"@@ -21,18 +22,17 @@\n jump\n-s\n+ed\n  over \n-the\n+a\n %0Alaz\n", false},
+		{"@@ -1 +1 @@\n-a\n+b\n", false},
+		{"@@ -1,3 +0,0 @@\n-abc\n", false},
+		{"@@ -0,0 +1,3 @@\n+abc\n", false},
+		{"@@ _0,0 +0,0 @@\n+abc\n", true},
+		{"Bad\nPatch\n", true},
+		{"@@ -1 +1 @@\n*a\n", true},
+		{"@@ -1 +1 @@\n-%zz\n", true},
+		{"@@ -0 +1 @@\n-a\n+b\n", true},
+		{"@@ -1 +0,1 @@\n-a\n+b\n", true},
+		{"@@ -1,99999999999999999999 +1 @@\n-a\n+b\n", true},
+		{"@@ -99999999999999999999 +1 @@\n-a\n+b\n", true},
+	} {
+		patches, err := dmp.PatchFromText(tc.Patch)
+		if tc.ErrorExpected {
+			assert.Error(t, err, fmt.Sprintf("Test case #%d, %#v", i, tc))
+		} else {
+			assert.NoError(t, err, fmt.Sprintf("Test case #%d, %#v", i, tc))
+			assert.Equal(t, tc.Patch, dmp.PatchToText(patches), fmt.Sprintf("Test case #%d, %#v", i, tc))
+		}
+	}
+
+	patches, err := dmp.PatchFromText("@@ -21,18 +22,17 @@\n jump\n-s\n+ed\n  over \n-the\n+a\n %0Alaz\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []Patch{
+		{
+			Diffs: []Diff{
+				{EQUAL, "jump"},
+				{DELETE, "s"},
+				{INSERT, "ed"},
+				{EQUAL, " over "},
+				{DELETE, "the"},
+				{INSERT, "a"},
+				{EQUAL, "\nlaz"},
+			},
+			Start1: 20, Start2: 21, Length1: 18, Length2: 17,
+		},
+	}, patches)
+}
+
+func TestPatchToText(t *testing.T) {
+	type TestCase struct {
+		Patches []Patch
+
+		Expected string
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{
+			[]Patch{},
+			"",
+		},
+		{
+			[]Patch{
+				{
+					Diffs: []Diff{
+						{DELETE, "`1234567890-=[]\\;',./"},
+						{INSERT, "~!@#$%^&*()_+{}|:\"<>?"},
+					},
+					Start1: 0, Start2: 0, Length1: 21, Length2: 21,
+				},
+			},
+			"@@ -1,21 +1,21 @@\n-%601234567890-=%5B%5D%5C;',./\n+~!@#$%25%5E&*()_+%7B%7D%7C:%22%3C%3E?\n",
+		},
+		{
+			[]Patch{
+				{
+					Diffs:  []Diff{{EQUAL, "a\tb "}, {INSERT, "é"}},
+					Start1: 0, Start2: 0, Length1: 4, Length2: 5,
+				},
+			},
+			"@@ -1,4 +1,5 @@\n a%09b \n+%C3%A9\n",
+		},
+	} {
+		actual := dmp.PatchToText(tc.Patches)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %#v", i, tc))
+
+		roundTrip, err := dmp.PatchFromText(actual)
+		assert.NoError(t, err, fmt.Sprintf("Test case #%d, %#v", i, tc))
+		assert.Equal(t, tc.Patches, roundTrip, fmt.Sprintf("Test case #%d, %#v", i, tc))
+	}
+}
+
+func TestPatchText(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text1 string
+		Text2 string
+		Text  string
+
+		Expected        string
+		ExpectedApplied []bool
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", "Hello world.", "Hello world.", []bool{}},
+		{"Exact match", "The quick brown fox jumps over the lazy dog.", "That quick brown fox jumped over a lazy dog.", "The quick brown fox jumps over the lazy dog.", "That quick brown fox jumped over a lazy dog.", []bool{true, true}},
+		{"Partial match", "The quick brown fox jumps over the lazy dog.", "That quick brown fox jumped over a lazy dog.", "The quick red rabbit jumps over the tired tiger.", "That quick red rabbit jumped over a tired tiger.", []bool{true, true}},
+		{"Failed match", "The quick brown fox jumps over the lazy dog.", "That quick brown fox jumped over a lazy dog.", "I am the very model of a modern major general.", "I am the very model of a modern major general.", []bool{false, false}},
+	} {
+		patchText := dmp.MakePatchText(tc.Text1, tc.Text2)
+		actual, applied, err := dmp.ApplyPatchText(patchText, tc.Text)
+		assert.NoError(t, err, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedApplied, applied, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// The one-shot gives the same patch as the steps it stands for.
+	text1, text2 := "The quick brown fox jumps over the lazy dog.", "That quick brown fox jumped over a lazy dog."
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffMainStrings(text1, text2, true))
+	assert.Equal(t, dmp.PatchToText(dmp.PatchMake(text1, diffs)), dmp.MakePatchText(text1, text2))
+
+	// A broken patch text is reported and the text is left alone.
+	actual, applied, err := dmp.ApplyPatchText("Bad\nPatch\n", "Hello world.")
+	assert.Error(t, err)
+	assert.Equal(t, "Hello world.", actual)
+	assert.Nil(t, applied)
+}
+
+func TestPatchString(t *testing.T) {
+	patch := Patch{
+		Diffs:  []Diff{{EQUAL, "jump"}, {DELETE, "s"}, {INSERT, "ed\n"}, {EQUAL, " over"}},
+		Start1: 20, Start2: 21, Length1: 10, Length2: 12,
+	}
+	assert.Equal(t, "@@ -21,10 +22,12 @@\n"+
+		"  Diff(EQUAL,\"jump\")\n"+
+		"  Diff(DELETE,\"s\")\n"+
+		"  Diff(INSERT,\"ed¶\")\n"+
+		"  Diff(EQUAL,\" over\")\n", patch.String())
+
+	assert.Equal(t, "@@ -0,0 +1 @@\n  Diff(INSERT,\"a\")\n", fmt.Sprint(Patch{Diffs: []Diff{{INSERT, "a"}}, Length2: 1}))
+}
@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// Diff the line-by-line content of two readers.  Each reader is consumed one
+// line at a time and only the unique lines are kept in memory, along with one
+// rune per line, so two large but similar files cost little more than the
+// lines that differ.  The diff is not refined below line granularity.
+func (dmp *DiffMatchPatch) DiffReaders(a, b io.Reader) ([]Diff, error) {
+	// '\x00' is a valid character, but various debuggers don't like it. So we'll insert a junk entry to avoid generating a null character.
+	lineArray := []string{""}
+	lineHash := make(map[string]int)
+
+	charsA, err := dmp.DiffLinesToRunesMungeReader(a, &lineArray, lineHash)
+	if err != nil {
+		return nil, err
+	}
+	charsB, err := dmp.DiffLinesToRunesMungeReader(b, &lineArray, lineHash)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := dmp.timeoutContext(context.Background())
+	defer cancel()
+
+	diffs := dmp.diffMainContext(ctx, charsA, charsB, false)
+
+	// Convert the diff back to original text.
+	return dmp.DiffCharsToLines(diffs, lineArray), nil
+}
+
+// Read r line by line, recording each unseen line in lineArray and lineHash,
+// and return one rune per line as encoded by intToRune.
+func (dmp *DiffMatchPatch) DiffLinesToRunesMungeReader(r io.Reader, lineArray *[]string, lineHash map[string]int) ([]rune, error) {
+	reader := bufio.NewReader(r)
+	chars := []rune{}
+
+	for {
+		var line string
+		var err error
+		if lineArrayFull(*lineArray) {
+			// Out of runes, the rest of the text becomes the last line.
+			var rest []byte
+			rest, err = io.ReadAll(reader)
+			line = string(rest)
+			if err == nil {
+				err = io.EOF
+			}
+		} else {
+			line, err = reader.ReadString('\n')
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if len(line) > 0 {
+			lineValue, ok := lineHash[line]
+			if !ok {
+				*lineArray = append(*lineArray, line)
+				lineValue = len(*lineArray) - 1
+				lineHash[line] = lineValue
+			}
+			chars = append(chars, intToRune(uint32(lineValue)))
+		}
+
+		if err == io.EOF {
+			return chars, nil
+		}
+	}
+}
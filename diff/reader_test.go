@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffReaders(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+
+		Expected []Diff
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", []Diff{}},
+		{"Equal", "alpha\nbeta\n", "alpha\nbeta\n", []Diff{{EQUAL, "alpha\nbeta\n"}}},
+		{"Insert lines", "", "alpha\nbeta\n", []Diff{{INSERT, "alpha\nbeta\n"}}},
+		{"Delete a line", "alpha\nbeta\ngamma\n", "alpha\ngamma\n", []Diff{{EQUAL, "alpha\n"}, {DELETE, "beta\n"}, {EQUAL, "gamma\n"}}},
+		{"Omit final newline", "alpha\n", "alpha\nbeta", []Diff{{EQUAL, "alpha\n"}, {INSERT, "beta"}}},
+	} {
+		actual, err := dmp.DiffReaders(strings.NewReader(tc.TextA), strings.NewReader(tc.TextB))
+		assert.NoError(t, err, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Long inputs round trip.
+	var a, b strings.Builder
+	for x := 0; x < 10000; x++ {
+		_, _ = fmt.Fprintf(&a, "line %d\n", x%100)
+		if x%1000 != 0 {
+			_, _ = fmt.Fprintf(&b, "line %d\n", x%100)
+		}
+	}
+	diffs, err := dmp.DiffReaders(strings.NewReader(a.String()), strings.NewReader(b.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, a.String(), dmp.DiffTextSource(diffs))
+	assert.Equal(t, b.String(), dmp.DiffTextResult(diffs))
+
+	// Read errors are returned.
+	readErr := errors.New("read failed")
+	_, err = dmp.DiffReaders(strings.NewReader("alpha\n"), io.MultiReader(strings.NewReader("alpha\n"), errReader{readErr}))
+	assert.Equal(t, readErr, err)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
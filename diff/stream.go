@@ -0,0 +1,108 @@
+package diff
+
+import "context"
+
+// DiffMainCallback is DiffMain which hands each diff to emit as soon as it
+// is final, instead of returning the whole list.  The regions are diffed in
+// order and merged on the fly, so a renderer can start on the head of a
+// large diff while the tail is still being computed, and only the regions
+// in progress are held in memory.
+// Edits are merged as DiffCleanupMerge does, except that single edits are
+// not shifted across the equalities around them, so a few diffs may be
+// split differently than DiffMain would split them.
+func (dmp *DiffMatchPatch) DiffMainCallback(textA, textB []rune, checklines bool, emit func(Diff)) {
+	merger := &diffStreamMerger{emit: emit}
+	ctx, cancel := dmp.timeoutContext(context.WithValue(context.Background(), diffStreamKey{}, merger))
+	defer cancel()
+
+	dmp.diffMainContext(ctx, textA, textB, checklines)
+	merger.close()
+}
+
+// The context key of the *diffStreamMerger of DiffMainCallback.  While it is
+// set the diffs of diffMainContext go to the merger as soon as they are in
+// order, rather than being returned.
+type diffStreamKey struct{}
+
+// Add diffs to the merger in ctx and return nothing, or without a merger
+// return diffs as they are.
+func streamDiffs(ctx context.Context, diffs []Diff) []Diff {
+	merger, _ := ctx.Value(diffStreamKey{}).(*diffStreamMerger)
+	if merger == nil {
+		return diffs
+	}
+	for _, aDiff := range diffs {
+		merger.add(aDiff)
+	}
+	return nil
+}
+
+// Whether the diffs of diffMainContext go to a merger.
+func streaming(ctx context.Context) bool {
+	merger, _ := ctx.Value(diffStreamKey{}).(*diffStreamMerger)
+	return merger != nil
+}
+
+// Joins the diffs of consecutive regions and passes them on once nothing
+// after them can change them any more.
+type diffStreamMerger struct {
+	emit func(Diff)
+	// The equality waiting for the edits after it.
+	equality string
+	// The edits since the last equality.
+	deleted, inserted []rune
+}
+
+func (m *diffStreamMerger) add(aDiff Diff) {
+	if len(aDiff.Text) == 0 {
+		return
+	}
+	switch aDiff.Type {
+	case DELETE:
+		m.deleted = append(m.deleted, []rune(aDiff.Text)...)
+	case INSERT:
+		m.inserted = append(m.inserted, []rune(aDiff.Text)...)
+	case EQUAL:
+		m.flush(aDiff.Text)
+	}
+}
+
+// Factor the common prefix and suffix out of the edits, emit the equality
+// before them and the edits, and hold next, the equality after them.
+func (m *diffStreamMerger) flush(next string) {
+	if len(m.deleted) == 0 && len(m.inserted) == 0 {
+		m.equality += next
+		return
+	}
+
+	commonLength := commonPrefixLength(m.deleted, m.inserted)
+	m.equality += string(m.deleted[:commonLength])
+	deleted := m.deleted[commonLength:]
+	inserted := m.inserted[commonLength:]
+
+	commonLength = commonSuffixLength(deleted, inserted)
+	suffix := string(deleted[len(deleted)-commonLength:])
+	deleted = deleted[:len(deleted)-commonLength]
+	inserted = inserted[:len(inserted)-commonLength]
+
+	if len(m.equality) > 0 {
+		m.emit(Diff{EQUAL, m.equality})
+	}
+	if len(deleted) > 0 {
+		m.emit(Diff{DELETE, string(deleted)})
+	}
+	if len(inserted) > 0 {
+		m.emit(Diff{INSERT, string(inserted)})
+	}
+	m.equality = suffix + next
+	m.deleted, m.inserted = m.deleted[:0], m.inserted[:0]
+}
+
+// Emit whatever is still held.
+func (m *diffStreamMerger) close() {
+	m.flush("")
+	if len(m.equality) > 0 {
+		m.emit(Diff{EQUAL, m.equality})
+		m.equality = ""
+	}
+}
@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMainCallback(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", ""},
+		{"Equality", "abc", "abc"},
+		{"Simple insertion", "abc", "ab123c"},
+		{"Simple deletion", "a123bc", "abc"},
+		{"Two insertions", "abc", "a123b456c"},
+		{"Two deletions", "a123b456c", "abc"},
+		{"Simple case", "a", "b"},
+		{"Bisection", "Apples are a fruit.", "Bananas are also fruit."},
+		{"Half match", "1234567890abcdefghij", "a345678z90abcdefghij"},
+		{"Runes", "日本語のテキスト", "日本のテキスト。"},
+	} {
+		var actual []Diff
+		dmp.DiffMainCallback([]rune(tc.TextA), []rune(tc.TextB), false, func(aDiff Diff) {
+			actual = append(actual, aDiff)
+		})
+		_, expected := dmp.DiffMain([]rune(tc.TextA), []rune(tc.TextB), false)
+		assert.Equal(t, expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// The line mode is streamed in one piece.
+	textA := strings.Repeat("a line of text\n", 20) + "the end\n"
+	textB := strings.Repeat("a line of text\n", 10) + "another line\n" + strings.Repeat("a line of text\n", 10) + "The End\n"
+	var streamed []Diff
+	dmp.DiffMainCallback([]rune(textA), []rune(textB), true, func(aDiff Diff) {
+		streamed = append(streamed, aDiff)
+	})
+	_, expected := dmp.DiffMain([]rune(textA), []rune(textB), true)
+	assert.Equal(t, expected, streamed)
+
+	// Long diffs are valid and merged.
+	dmp.Diff_Timeout = 0
+	for n := 1000; n <= 20000; n += 1000 {
+		textA, textB := randomEditedText(n, n/50)
+		var actual []Diff
+		dmp.DiffMainCallback(textA, textB, false, func(aDiff Diff) {
+			actual = append(actual, aDiff)
+		})
+		if !assert.NoError(t, dmp.DiffValidate(actual, string(textA), string(textB)), fmt.Sprintf("Length %d", n)) {
+			break
+		}
+		for k, aDiff := range actual {
+			assert.NotEmpty(t, aDiff.Text, fmt.Sprintf("Length %d, diff %d", n, k))
+			if k > 0 {
+				assert.NotEqual(t, actual[k-1].Type, aDiff.Type, fmt.Sprintf("Length %d, diff %d", n, k))
+			}
+		}
+		// Without the shifting of DiffCleanupMerge a few edits may stay apart.
+		_, expected := dmp.DiffMain(textA, textB, false)
+		levenshtein := dmp.DiffLevenshtein(expected)
+		assert.GreaterOrEqual(t, dmp.DiffLevenshtein(actual), levenshtein, fmt.Sprintf("Length %d", n))
+		assert.LessOrEqual(t, dmp.DiffLevenshtein(actual), levenshtein+levenshtein/10+1, fmt.Sprintf("Length %d", n))
+	}
+}
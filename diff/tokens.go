@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"context"
+	"slices"
+)
+
+// TokenDiff is one run of a diff of token slices, as DiffSlices returns it.
+type TokenDiff[T comparable] struct {
+	Type   Operation
+	Tokens []T
+}
+
+// DiffSlices diffs two slices of any comparable tokens, such as chat
+// messages or AST nodes, with the same bisect core as DiffMain.  Adjacent
+// edits are merged as DiffCleanupMerge does, with deletions before
+// insertions, and the runs share their backing arrays with a and b.
+// Go methods can't have type parameters, so there are no Diff_Timeout and
+// half match speedup here: the diff is always exact, and its time grows with
+// the length times the number of edits.
+func DiffSlices[T comparable](a, b []T) []TokenDiff[T] {
+	return diffSlices(context.Background(), a, b, defaultBisectCheckInterval)
+}
+
+// The token counterpart of diffMainContext, shared with DiffMainBytes.  The
+// bisect checks ctx every checkInterval steps.
+func diffSlices[T comparable](ctx context.Context, a, b []T, checkInterval int) []TokenDiff[T] {
+	// Check for equality (speedup).
+	if slices.Equal(a, b) {
+		var diffs []TokenDiff[T]
+		if len(a) > 0 {
+			diffs = append(diffs, TokenDiff[T]{EQUAL, a})
+		}
+		return diffs
+	}
+
+	// Trim off common prefix and suffix (speedup).
+	commonLength := commonPrefixLength(a, b)
+	commonPrefix := a[:commonLength]
+	a = a[commonLength:]
+	b = b[commonLength:]
+
+	commonLength = commonSuffixLength(a, b)
+	commonSuffix := a[len(a)-commonLength:]
+	a = a[:len(a)-commonLength]
+	b = b[:len(b)-commonLength]
+
+	// Compute the diff on the middle block.
+	var diffs []TokenDiff[T]
+	if len(commonPrefix) > 0 {
+		diffs = append(diffs, TokenDiff[T]{EQUAL, commonPrefix})
+	}
+	diffs = append(diffs, diffComputeSlices(ctx, a, b, checkInterval)...)
+	if len(commonSuffix) > 0 {
+		diffs = append(diffs, TokenDiff[T]{EQUAL, commonSuffix})
+	}
+	return diffMergeTokens(diffs)
+}
+
+// The token counterpart of DiffCompute, without the half match and line
+// mode speedups.
+func diffComputeSlices[T comparable](ctx context.Context, a, b []T, checkInterval int) []TokenDiff[T] {
+	if len(a) == 0 {
+		// Just add some tokens (speedup).
+		return []TokenDiff[T]{{INSERT, b}}
+	}
+	if len(b) == 0 {
+		// Just delete some tokens (speedup).
+		return []TokenDiff[T]{{DELETE, a}}
+	}
+
+	longtext, shorttext, op := b, a, INSERT
+	if len(a) > len(b) {
+		longtext, shorttext, op = a, b, DELETE
+	}
+	if i := slicesIndex(longtext, shorttext); i != -1 {
+		// Shorter slice is inside the longer slice (speedup).
+		return []TokenDiff[T]{
+			{op, longtext[:i]},
+			{EQUAL, shorttext},
+			{op, longtext[i+len(shorttext):]},
+		}
+	}
+
+	if len(shorttext) > 1 {
+		if x, y, found := diffBisectMiddleSnake(ctx, a, b, checkInterval); found {
+			diffs := diffSlices(ctx, a[:x], b[:y], checkInterval)
+			return append(diffs, diffSlices(ctx, a[x:], b[y:], checkInterval)...)
+		}
+	}
+
+	// A single token which can't be an equality, nothing in common or the
+	// diff took too long.
+	return []TokenDiff[T]{{DELETE, a}, {INSERT, b}}
+}
+
+// The index of the first instance of pattern in target, or -1.
+func slicesIndex[T comparable](target, pattern []T) int {
+	for i := 0; i+len(pattern) <= len(target); i++ {
+		if slices.Equal(target[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Merge the edits between each pair of equalities into one deletion and one
+// insertion, factor out their common prefix and suffix, and join adjacent
+// equalities.  DiffCleanupMerge does this on runes, which would mangle bytes
+// split inside a rune.  The merged runs are copies wherever two runs were
+// joined, so appending never writes into a or b.
+func diffMergeTokens[T comparable](diffs []TokenDiff[T]) []TokenDiff[T] {
+	var merged []TokenDiff[T]
+	var tokensDelete, tokensInsert []T
+
+	add := func(op Operation, tokens []T) {
+		if len(tokens) == 0 {
+			return
+		}
+		if last := len(merged) - 1; last >= 0 && merged[last].Type == op {
+			merged[last].Tokens = append(slices.Clip(merged[last].Tokens), tokens...)
+		} else {
+			merged = append(merged, TokenDiff[T]{op, tokens})
+		}
+	}
+	flush := func() {
+		commonLength := commonPrefixLength(tokensDelete, tokensInsert)
+		add(EQUAL, tokensDelete[:commonLength])
+		tokensDelete = tokensDelete[commonLength:]
+		tokensInsert = tokensInsert[commonLength:]
+
+		commonLength = commonSuffixLength(tokensDelete, tokensInsert)
+		suffix := tokensDelete[len(tokensDelete)-commonLength:]
+		tokensDelete = tokensDelete[:len(tokensDelete)-commonLength]
+		tokensInsert = tokensInsert[:len(tokensInsert)-commonLength]
+
+		add(DELETE, tokensDelete)
+		add(INSERT, tokensInsert)
+		add(EQUAL, suffix)
+		tokensDelete, tokensInsert = nil, nil
+	}
+
+	for _, aDiff := range diffs {
+		switch aDiff.Type {
+		case DELETE:
+			tokensDelete = append(slices.Clip(tokensDelete), aDiff.Tokens...)
+		case INSERT:
+			tokensInsert = append(slices.Clip(tokensInsert), aDiff.Tokens...)
+		case EQUAL:
+			if len(aDiff.Tokens) > 0 {
+				flush()
+				add(EQUAL, aDiff.Tokens)
+			}
+		}
+	}
+	flush()
+	return merged
+}
@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSlices(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		TextB string
+
+		Expected []TokenDiff[string]
+	}
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", nil},
+		{"Equality", "the cat sat", "the cat sat", []TokenDiff[string]{{EQUAL, []string{"the", "cat", "sat"}}}},
+		{"Simple insertion", "the cat sat", "the black cat sat", []TokenDiff[string]{{EQUAL, []string{"the"}}, {INSERT, []string{"black"}}, {EQUAL, []string{"cat", "sat"}}}},
+		{"Simple deletion", "the black cat sat", "the cat", []TokenDiff[string]{{EQUAL, []string{"the"}}, {DELETE, []string{"black"}}, {EQUAL, []string{"cat"}}, {DELETE, []string{"sat"}}}},
+		{"Simple case", "cat", "dog", []TokenDiff[string]{{DELETE, []string{"cat"}}, {INSERT, []string{"dog"}}}},
+		{"Bisection", "a cat sat on the mat", "the dog sat on a mat", []TokenDiff[string]{{DELETE, []string{"a", "cat"}}, {INSERT, []string{"the", "dog"}}, {EQUAL, []string{"sat", "on"}}, {DELETE, []string{"the"}}, {INSERT, []string{"a"}}, {EQUAL, []string{"mat"}}}},
+	} {
+		actual := DiffSlices(strings.Fields(tc.TextA), strings.Fields(tc.TextB))
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Structs diff by value.
+	type message struct {
+		From string
+		Text string
+	}
+	a := []message{{"ann", "hi"}, {"bob", "hello"}, {"ann", "how are you?"}}
+	b := []message{{"ann", "hi"}, {"ann", "how are you?"}, {"bob", "fine"}}
+	assert.Equal(t, []TokenDiff[message]{
+		{EQUAL, a[:1]},
+		{DELETE, a[1:2]},
+		{EQUAL, a[2:]},
+		{INSERT, b[2:]},
+	}, DiffSlices(a, b))
+
+	// Bytes diff as DiffMainBytes diffs them.
+	dmp := New()
+	dmp.Diff_Timeout = 0
+	runesA, runesB := randomEditedText(5000, 100)
+	textA, textB := []byte(string(runesA)), []byte(string(runesB))
+	var diffs []Diff
+	for _, aDiff := range DiffSlices(textA, textB) {
+		diffs = append(diffs, Diff{aDiff.Type, string(aDiff.Tokens)})
+	}
+	assert.Equal(t, dmp.DiffMainBytes(textA, textB), diffs)
+
+	// The inputs are left alone.
+	assert.Equal(t, string(runesA), string(textA))
+	assert.Equal(t, string(runesB), string(textB))
+}
@@ -0,0 +1,311 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// One line of a unified diff, with its newline unless it is the last line
+// of a text which doesn't end in one.
+type unifiedLine struct {
+	Type Operation
+	Text string
+}
+
+// DiffToUnifiedDiff renders diffs in the unified format of `diff -u`, with
+// contextLines unchanged lines around each hunk, so it can be fed to patch(1)
+// and similar tools.  Identical texts give an empty string.
+// The format is line based: any line touched by an edit is shown as deleted
+// and inserted in full.  Diffs from DiffLineMode or DiffReaders give the
+// smallest hunks, character diffs are fine but may pull in more lines.
+func DiffToUnifiedDiff(diffs []Diff, fromFile, toFile string, contextLines int) string {
+	contextLines = max(contextLines, 0)
+	lines := diffLines(diffs)
+
+	var text bytes.Buffer
+	// Line numbers before the current line, in the old and new text.
+	lineA, lineB := 0, 0
+	pointer := 0
+	for pointer < len(lines) {
+		// Find the next change.
+		change := pointer
+		for change < len(lines) && lines[change].Type == EQUAL {
+			change++
+		}
+		if change == len(lines) {
+			break
+		}
+		start := max(change-contextLines, pointer)
+		// Lines up to the hunk are unchanged.
+		lineA += start - pointer
+		lineB += start - pointer
+
+		// Extend the hunk over changes which are separated by at most twice the
+		// context, those hunks would overlap.
+		end := change
+		for {
+			for end < len(lines) && lines[end].Type != EQUAL {
+				end++
+			}
+			next := end
+			for next < len(lines) && lines[next].Type == EQUAL {
+				next++
+			}
+			if next == len(lines) || next-end > 2*contextLines {
+				end = min(end+contextLines, len(lines))
+				break
+			}
+			end = next
+		}
+
+		hunk := lines[start:end]
+		lengthA, lengthB := 0, 0
+		for _, line := range hunk {
+			if line.Type != INSERT {
+				lengthA++
+			}
+			if line.Type != DELETE {
+				lengthB++
+			}
+		}
+
+		if text.Len() == 0 {
+			_, _ = text.WriteString("--- " + fromFile + "\n")
+			_, _ = text.WriteString("+++ " + toFile + "\n")
+		}
+		_, _ = text.WriteString("@@ -" + unifiedCoords(lineA, lengthA) + " +" + unifiedCoords(lineB, lengthB) + " @@\n")
+		for _, line := range hunk {
+			switch line.Type {
+			case INSERT:
+				_, _ = text.WriteString("+")
+			case DELETE:
+				_, _ = text.WriteString("-")
+			case EQUAL:
+				_, _ = text.WriteString(" ")
+			}
+			_, _ = text.WriteString(line.Text)
+			if !strings.HasSuffix(line.Text, "\n") {
+				_, _ = text.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		lineA += lengthA
+		lineB += lengthB
+		pointer = end
+	}
+	return text.String()
+}
+
+// Split diffs into whole lines.  A line is only EQUAL if it is unchanged in
+// both texts, otherwise the old lines are deleted and the new ones inserted,
+// with the deletions of each block of changes first.
+func diffLines(diffs []Diff) []unifiedLine {
+	var lines []unifiedLine
+	// Complete lines of the current block of changes.
+	var blockA, blockB []string
+	// The unfinished line of each text.
+	var lineA, lineB string
+	changed := false
+
+	flush := func() {
+		for _, line := range blockA {
+			lines = append(lines, unifiedLine{DELETE, line})
+		}
+		for _, line := range blockB {
+			lines = append(lines, unifiedLine{INSERT, line})
+		}
+		blockA, blockB = nil, nil
+	}
+	// Both texts reached the end of a line.
+	endLines := func() {
+		if !changed {
+			flush()
+			lines = append(lines, unifiedLine{EQUAL, lineA})
+		} else {
+			if len(lineA) != 0 {
+				blockA = append(blockA, lineA)
+			}
+			if len(lineB) != 0 {
+				blockB = append(blockB, lineB)
+			}
+		}
+		lineA, lineB = "", ""
+		changed = false
+	}
+
+	for _, aDiff := range diffs {
+		text := aDiff.Text
+		for len(text) > 0 {
+			piece := text
+			if i := strings.IndexByte(text, '\n'); i != -1 {
+				piece = text[:i+1]
+			}
+			text = text[len(piece):]
+			complete := strings.HasSuffix(piece, "\n")
+
+			switch aDiff.Type {
+			case EQUAL:
+				lineA += piece
+				lineB += piece
+				if complete {
+					endLines()
+				}
+			case DELETE:
+				lineA += piece
+				changed = true
+				if complete {
+					blockA = append(blockA, lineA)
+					lineA = ""
+					// Nothing is left over if the new text is at a line start too.
+					changed = len(lineB) != 0
+				}
+			case INSERT, MOVE:
+				lineB += piece
+				changed = true
+				if complete {
+					blockB = append(blockB, lineB)
+					lineB = ""
+					changed = len(lineA) != 0
+				}
+			}
+		}
+	}
+	if len(lineA) != 0 || len(lineB) != 0 {
+		// The last lines have no newline.
+		endLines()
+	}
+	flush()
+	return lines
+}
+
+var unifiedHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// PatchFromUnifiedDiff parses the unified diff of one file, as written by
+// `diff -u`, `git diff` or DiffToUnifiedDiff, into patches for PatchApply.
+// Anything before the first hunk, such as the file headers, is skipped.  A
+// "\ No newline at end of file" marker drops the newline of the line before
+// it.  Unified diffs count lines, so the patches carry their SourceLine and
+// PatchApply finds their offsets in the text.
+func PatchFromUnifiedDiff(text string) ([]Patch, error) {
+	patches := []Patch{}
+	lines := strings.SplitAfter(text, "\n")
+	pointer := 0
+	for pointer < len(lines) && !strings.HasPrefix(lines[pointer], "@@") {
+		pointer++
+	}
+
+	for pointer < len(lines) {
+		if len(lines[pointer]) == 0 {
+			// The end of the text.
+			pointer++
+			continue
+		}
+		m := unifiedHeaderRegex.FindStringSubmatch(lines[pointer])
+		if m == nil {
+			return patches, fmt.Errorf("invalid unified diff hunk header: %q", lines[pointer])
+		}
+		start1, length1, err := parseUnifiedCoords(m[1], m[2])
+		if err != nil {
+			return patches, fmt.Errorf("invalid unified diff hunk header: %q: %w", lines[pointer], err)
+		}
+		_, length2, err := parseUnifiedCoords(m[3], m[4])
+		if err != nil {
+			return patches, fmt.Errorf("invalid unified diff hunk header: %q: %w", lines[pointer], err)
+		}
+		patch := Patch{SourceLine: start1}
+		if length1 == 0 {
+			// Empty ranges give the line before them.
+			patch.SourceLine++
+		}
+		pointer++
+
+		for length1 > 0 || length2 > 0 {
+			if pointer == len(lines) || len(lines[pointer]) == 0 {
+				return patches, fmt.Errorf("unexpected end of unified diff hunk %q", m[0])
+			}
+			line := lines[pointer]
+			var op Operation
+			switch line[0] {
+			case ' ', '\n', '\r':
+				// Some tools strip the space off empty context lines.
+				op = EQUAL
+				length1--
+				length2--
+			case '-':
+				op = DELETE
+				length1--
+			case '+':
+				op = INSERT
+				length2--
+			default:
+				return patches, fmt.Errorf("invalid unified diff line: %q", line)
+			}
+			if length1 < 0 || length2 < 0 {
+				return patches, fmt.Errorf("unified diff hunk %q is longer than its header", m[0])
+			}
+			if op == EQUAL && line[0] != ' ' {
+				line = " " + line
+			}
+			line = line[1:]
+			pointer++
+			if pointer < len(lines) && strings.HasPrefix(lines[pointer], "\\") {
+				// \ No newline at end of file
+				line = strings.TrimSuffix(line, "\n")
+				pointer++
+			}
+
+			if last := len(patch.Diffs) - 1; last >= 0 && patch.Diffs[last].Type == op {
+				patch.Diffs[last].Text += line
+			} else {
+				patch.Diffs = append(patch.Diffs, Diff{op, line})
+			}
+			n := utf8.RuneCountInString(line)
+			if op != INSERT {
+				patch.Length1 += n
+			}
+			if op != DELETE {
+				patch.Length2 += n
+			}
+		}
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// parseUnifiedCoords converts the start and optional length of a unified diff
+// header, the length defaults to 1.  Only an empty range may start at line
+// 0, which is before the first line.
+func parseUnifiedCoords(startText, lengthText string) (int, int, error) {
+	// The header regex only matches digits, so Atoi can only fail on overflow.
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	length := 1
+	if len(lengthText) != 0 {
+		if length, err = strconv.Atoi(lengthText); err != nil {
+			return 0, 0, err
+		}
+	}
+	if start == 0 && length != 0 {
+		return 0, 0, fmt.Errorf("range of %d lines starts at line 0", length)
+	}
+	return start, length, nil
+}
+
+// Format the start and length of a hunk the way GNU diff does: 1-based, the
+// length left out if it is 1, and for an empty range the line before it.
+func unifiedCoords(start, length int) string {
+	switch length {
+	case 0:
+		return strconv.Itoa(start) + ",0"
+	case 1:
+		return strconv.Itoa(start + 1)
+	default:
+		return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+	}
+}
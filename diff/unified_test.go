@@ -0,0 +1,188 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffToUnifiedDiff(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Diffs        []Diff
+		ContextLines int
+
+		Expected string
+	}
+
+	for i, tc := range []TestCase{
+		{"Null case", nil, 3, ""},
+		{"No changes", []Diff{{EQUAL, "a\nb\n"}}, 3, ""},
+		{
+			"Changed line",
+			[]Diff{{EQUAL, "a\n"}, {DELETE, "b\n"}, {INSERT, "B\n"}, {EQUAL, "c\n"}},
+			3,
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"Character diff",
+			[]Diff{{EQUAL, "a\nb"}, {DELETE, "x"}, {INSERT, "y"}, {EQUAL, "c\nd\n"}},
+			3,
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-bxc\n+byc\n d\n",
+		},
+		{
+			"No context",
+			[]Diff{{EQUAL, "a\nb\n"}, {DELETE, "c\n"}, {EQUAL, "d\ne\n"}},
+			0,
+			"--- old\n+++ new\n@@ -3 +2,0 @@\n-c\n",
+		},
+		{
+			"Insert into an empty file",
+			[]Diff{{INSERT, "a\nb\n"}},
+			3,
+			"--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			"Separate hunks",
+			[]Diff{{DELETE, "1\n"}, {EQUAL, "2\n3\n4\n5\n6\n"}, {INSERT, "7\n"}},
+			1,
+			"--- old\n+++ new\n@@ -1,2 +1 @@\n-1\n 2\n@@ -6 +5,2 @@\n 6\n+7\n",
+		},
+		{
+			"Hunks joined by shared context",
+			[]Diff{{DELETE, "1\n"}, {EQUAL, "2\n3\n"}, {INSERT, "4\n"}},
+			1,
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n-1\n 2\n 3\n+4\n",
+		},
+		{
+			"No newline at end of file",
+			[]Diff{{EQUAL, "a\n"}, {DELETE, "b"}, {INSERT, "b\n"}},
+			3,
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	} {
+		actual := DiffToUnifiedDiff(tc.Diffs, "old", "new", tc.ContextLines)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestPatchFromUnifiedDiff(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Diff string
+
+		Expected []Patch
+	}
+
+	for i, tc := range []TestCase{
+		{"Null case", "", []Patch{}},
+		{
+			"Changed line",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+			[]Patch{
+				{
+					Diffs:   []Diff{{EQUAL, "a\n"}, {DELETE, "b\n"}, {INSERT, "B\n"}, {EQUAL, "c\n"}},
+					Length1: 6, Length2: 6, SourceLine: 1,
+				},
+			},
+		},
+		{
+			"Git headers and lengths of 1",
+			"diff --git a/f b/f\nindex 83db48f..bf269f4 100644\n--- a/f\n+++ b/f\n@@ -2 +2 @@ func main() {\n-ä\n+ö\n",
+			[]Patch{
+				{
+					Diffs:   []Diff{{DELETE, "ä\n"}, {INSERT, "ö\n"}},
+					Length1: 2, Length2: 2, SourceLine: 2,
+				},
+			},
+		},
+		{
+			"Insertion after a line and stripped empty context",
+			"@@ -3,0 +4,2 @@\n+x\n+y\n@@ -9,2 +11,2 @@\n\n-z\n+Z\n",
+			[]Patch{
+				{
+					Diffs:   []Diff{{INSERT, "x\ny\n"}},
+					Length1: 0, Length2: 4, SourceLine: 4,
+				},
+				{
+					Diffs:   []Diff{{EQUAL, "\n"}, {DELETE, "z\n"}, {INSERT, "Z\n"}},
+					Length1: 3, Length2: 3, SourceLine: 9,
+				},
+			},
+		},
+		{
+			"No newline at end of file",
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+			[]Patch{
+				{
+					Diffs:   []Diff{{EQUAL, "a\n"}, {DELETE, "b"}, {INSERT, "b\n"}},
+					Length1: 3, Length2: 4, SourceLine: 1,
+				},
+			},
+		},
+	} {
+		actual, err := PatchFromUnifiedDiff(tc.Diff)
+		assert.NoError(t, err, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	for i, tc := range []TestCase{
+		{"Bad header", "@@ -1,a +1 @@\n", nil},
+		{"Overflowing start", "@@ -99999999999999999999999 +1 @@\n-a\n+b\n", nil},
+		{"Overflowing length", "@@ -1,99999999999999999999999 +1 @@\n-a\n+b\n", nil},
+		{"Lines from line 0", "@@ -0,1 +0,1 @@\n-a\n+b\n", nil},
+		{"Line 0", "@@ -1 +0 @@\n-a\n+b\n", nil},
+		{"Bad line", "@@ -1 +1 @@\n*a\n", nil},
+		{"Truncated hunk", "@@ -1,2 +1,2 @@\n a\n", nil},
+		{"Hunk longer than its header", "@@ -1 +1 @@\n-a\n-b\n+c\n", nil},
+		{"Second file", "--- a\n+++ a\n@@ -1 +1 @@\n-a\n+b\n--- b\n+++ b\n@@ -1 +1 @@\n-a\n+b\n", nil},
+	} {
+		_, err := PatchFromUnifiedDiff(tc.Diff)
+		assert.Error(t, err, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestPatchApplyUnifiedDiff(t *testing.T) {
+	dmp := New()
+
+	// Hunks far apart, which can only be found through their lines.
+	var a, b strings.Builder
+	for x := 0; x < 500; x++ {
+		_, _ = fmt.Fprintf(&a, "line %d\n", x)
+		switch x {
+		case 10:
+			_, _ = b.WriteString("changed\n")
+		case 250:
+		case 400:
+			_, _ = fmt.Fprintf(&b, "line %d\nadded\n", x)
+		default:
+			_, _ = fmt.Fprintf(&b, "line %d\n", x)
+		}
+	}
+	textA, textB := a.String(), b.String()
+
+	_, diffs := dmp.DiffMain([]rune(textA), []rune(textB), false)
+	patches, err := PatchFromUnifiedDiff(DiffToUnifiedDiff(diffs, "a", "b", 3))
+	assert.NoError(t, err)
+	assert.Len(t, patches, 3)
+	actual, results := dmp.PatchApply(patches, textA)
+	assert.Equal(t, textB, actual)
+	// PatchApply reports on the hunks after PatchSplitMax.
+	assert.NotContains(t, results, false)
+
+	// Lines added above the hunks shift them, as with patch(1).
+	actual, results = dmp.PatchApply(patches, "new\n"+textA)
+	assert.Equal(t, "new\n"+textB, actual)
+	assert.NotContains(t, results, false)
+
+	// Missing final newlines.
+	patches, err = PatchFromUnifiedDiff(DiffToUnifiedDiff([]Diff{{EQUAL, "a\n"}, {DELETE, "b"}, {INSERT, "c"}}, "a", "b", 3))
+	assert.NoError(t, err)
+	actual, results = dmp.PatchApply(patches, "a\nb")
+	assert.Equal(t, "a\nc", actual)
+	assert.Equal(t, []bool{true}, results)
+}
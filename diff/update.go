@@ -0,0 +1,69 @@
+package diff
+
+import "unicode/utf8"
+
+// Runes on either side of an edit which DiffUpdate diffs again, so the new
+// diff can settle across the edges of the window.
+const diffUpdateMargin = 16
+
+// DiffUpdate turns prev, a diff of some text A to oldB, into a diff of A to
+// newB.  Only the part of prev around the runes which changed between oldB
+// and newB is diffed again, which makes it much cheaper than DiffMain for the
+// small edits of a live editor.  The result is valid but may differ from what
+// DiffMain would give.  If prev is not a diff to oldB, A is diffed against
+// newB from scratch.
+func (dmp *DiffMatchPatch) DiffUpdate(prev []Diff, oldB, newB string) []Diff {
+	if dmp.DiffTextResult(prev) != oldB {
+		return dmp.DiffMainStrings(dmp.DiffTextSource(prev), newB, false)
+	}
+
+	oldRunes := []rune(oldB)
+	newRunes := []rune(newB)
+	prefix := commonPrefixLength(oldRunes, newRunes)
+	if prefix == len(oldRunes) && prefix == len(newRunes) {
+		return DiffsClone(prev)
+	}
+	suffix := commonSuffixLength(oldRunes[prefix:], newRunes[prefix:])
+
+	// The window of oldB to diff again, and the same window of newB.
+	start := max(prefix-diffUpdateMargin, 0)
+	oldEnd := min(len(oldRunes)-suffix+diffUpdateMargin, len(oldRunes))
+	newEnd := len(newRunes) - (len(oldRunes) - oldEnd)
+
+	head, rest := splitDiffsAtResult(prev, start)
+	middle, tail := splitDiffsAtResult(rest, oldEnd-start)
+	// The window is small, diff it rune by rune.
+	_, middle = dmp.DiffMain([]rune(dmp.DiffTextSource(middle)), newRunes[start:newEnd], false)
+
+	diffs := make([]Diff, 0, len(head)+len(middle)+len(tail))
+	diffs = append(diffs, head...)
+	diffs = append(diffs, middle...)
+	diffs = append(diffs, tail...)
+	_, diffs = dmp.DiffCleanupMerge(diffs)
+	return diffs
+}
+
+// Split diffs at the rune offset loc of their result text, cutting the diff
+// which spans loc in two.  Deletions at loc go to the second part.  diffs is
+// not changed.
+func splitDiffsAtResult(diffs []Diff, loc int) ([]Diff, []Diff) {
+	pointer := 0
+	for i, aDiff := range diffs {
+		if pointer == loc {
+			return diffs[:i:i], diffs[i:]
+		}
+		if aDiff.Type == DELETE {
+			continue
+		}
+		textLen := utf8.RuneCountInString(aDiff.Text)
+		if pointer+textLen > loc {
+			runes := []rune(aDiff.Text)
+			cut := loc - pointer
+			head := append(diffs[:i:i], Diff{aDiff.Type, string(runes[:cut])})
+			rest := append([]Diff{{aDiff.Type, string(runes[cut:])}}, diffs[i+1:]...)
+			return head, rest
+		}
+		pointer += textLen
+	}
+	return diffs, nil
+}
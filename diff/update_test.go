@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffUpdate(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		TextA string
+		OldB  string
+		NewB  string
+	}
+
+	dmp := New()
+
+	for i, tc := range []TestCase{
+		{"Null case", "", "", ""},
+		{"No change", "the cat sat", "the dog sat", "the dog sat"},
+		{"Typing", "the cat sat", "the dog sat", "the dogs sat"},
+		{"Undo", "the cat sat", "the dog sat", "the cat sat"},
+		{"Edit at the start", "the cat sat on the mat", "the cat sat on a mat", "A cat sat on a mat"},
+		{"Edit at the end", "the cat sat on the mat", "the cat sat on a mat", "the cat sat on a mat!"},
+		{"Clear", "the cat sat", "the dog sat", ""},
+		{"From empty", "the cat sat", "", "the"},
+		{"Runes", "日本語のテキスト", "日本のテキスト", "日本語のテキスト。"},
+	} {
+		_, prev := dmp.DiffMain([]rune(tc.TextA), []rune(tc.OldB), false)
+		_, expected := dmp.DiffMain([]rune(tc.TextA), []rune(tc.NewB), false)
+
+		actual := dmp.DiffUpdate(prev, tc.OldB, tc.NewB)
+		assert.Equal(t, expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Keystrokes in a long text, the window around each edit is diffed again.
+	r := rand.New(rand.NewSource(1))
+	words := strings.Fields("the quick brown fox jumps over the lazy dog and runs away into the woods")
+	var text strings.Builder
+	for text.Len() < 20000 {
+		_, _ = text.WriteString(words[r.Intn(len(words))] + " ")
+	}
+	textA := text.String()
+	textB := textA
+	diffs := []Diff{{EQUAL, textA}}
+	for i := 0; i < 200; i++ {
+		newB := []rune(textB)
+		at := r.Intn(len(newB))
+		if r.Intn(3) == 0 {
+			newB = append(newB[:at], newB[at+1:]...)
+		} else {
+			newB = append(newB[:at], append([]rune{rune('a' + r.Intn(26))}, newB[at:]...)...)
+		}
+		prev := DiffsClone(diffs)
+		diffs = dmp.DiffUpdate(diffs, textB, string(newB))
+		textB = string(newB)
+		if !assert.NoError(t, dmp.DiffValidate(diffs, textA, textB), fmt.Sprintf("Keystroke %d", i)) {
+			break
+		}
+		// The previous diff is left alone.
+		assert.NoError(t, dmp.DiffValidate(prev, textA, dmp.DiffTextResult(prev)))
+	}
+
+	// A diff which doesn't end in oldB is replaced.
+	actual := dmp.DiffUpdate([]Diff{{DELETE, "ab"}, {INSERT, "cd"}}, "xy", "ab")
+	assert.Equal(t, []Diff{{EQUAL, "ab"}}, actual)
+}
+
+func TestSplitDiffsAtResult(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Diffs    []Diff
+		Location int
+
+		ExpectedHead []Diff
+		ExpectedRest []Diff
+	}
+
+	diffs := []Diff{{EQUAL, "ab"}, {DELETE, "x"}, {INSERT, "cd"}, {EQUAL, "é"}}
+	for i, tc := range []TestCase{
+		{"Null case", nil, 0, nil, nil},
+		{"Start", diffs, 0, []Diff{}, diffs},
+		{"Inside an equality", diffs, 1, []Diff{{EQUAL, "a"}}, []Diff{{EQUAL, "b"}, {DELETE, "x"}, {INSERT, "cd"}, {EQUAL, "é"}}},
+		{"Before a deletion", diffs, 2, []Diff{{EQUAL, "ab"}}, []Diff{{DELETE, "x"}, {INSERT, "cd"}, {EQUAL, "é"}}},
+		{"Inside an insertion", diffs, 3, []Diff{{EQUAL, "ab"}, {DELETE, "x"}, {INSERT, "c"}}, []Diff{{INSERT, "d"}, {EQUAL, "é"}}},
+		{"End", diffs, 5, diffs, nil},
+	} {
+		head, rest := splitDiffsAtResult(tc.Diffs, tc.Location)
+		assert.Equal(t, tc.ExpectedHead, head, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+		assert.Equal(t, tc.ExpectedRest, rest, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func BenchmarkDiffUpdate(b *testing.B) {
+	runesA, runesB := randomEditedText(20000, 200)
+	textA, oldB := string(runesA), string(runesB)
+	newB := oldB[:10000] + "x" + oldB[10000:]
+	dmp := New()
+	_, prev := dmp.DiffMain(runesA, runesB, false)
+
+	b.Run("Update", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dmp.DiffUpdate(prev, oldB, newB)
+		}
+	})
+	b.Run("Recompute", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dmp.DiffMainStrings(textA, newB, false)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package diff
+
+import "unicode/utf8"
+
+// The offsets of this package count runes, JavaScript counts UTF-16 code
+// units, in which runes outside the Basic Multilingual Plane such as emoji
+// take two.  These helpers map between the two, so diffs computed in Go can
+// place cursors and selections in the browser.
+
+// RuneOffsetToUTF16 returns the UTF-16 offset in text of the rune offset
+// runeOffset.  Offsets are clamped to the text, invalid UTF-8 counts as one
+// U+FFFD each, as it does once the text reaches JavaScript.
+func RuneOffsetToUTF16(text string, runeOffset int) int {
+	offset := 0
+	for _, r := range text {
+		if runeOffset <= 0 {
+			break
+		}
+		offset += utf16Len(r)
+		runeOffset--
+	}
+	return offset
+}
+
+// UTF16OffsetToRune returns the rune offset in text of the UTF-16 offset
+// utf16Offset, the reverse of RuneOffsetToUTF16.  An offset between the two
+// halves of a surrogate pair maps to the rune they encode.
+func UTF16OffsetToRune(text string, utf16Offset int) int {
+	offset := 0
+	for _, r := range text {
+		utf16Offset -= utf16Len(r)
+		if utf16Offset < 0 {
+			break
+		}
+		offset++
+	}
+	return offset
+}
+
+// The number of UTF-16 code units of r.
+func utf16Len(r rune) int {
+	if r > 0xFFFF && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
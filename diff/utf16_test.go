@@ -0,0 +1,69 @@
+package diff
+
+import (
+	"fmt"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuneOffsetToUTF16(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text       string
+		RuneOffset int
+
+		Expected int
+	}
+
+	for i, tc := range []TestCase{
+		{"Null case", "", 0, 0},
+		{"ASCII", "abc", 2, 2},
+		{"BMP", "日本語", 2, 2},
+		{"Before an emoji", "a😀b", 1, 1},
+		{"After an emoji", "a😀b", 2, 3},
+		{"End", "a😀b", 3, 4},
+		{"Past the end", "a😀b", 10, 4},
+		{"Negative", "a😀b", -1, 0},
+		{"Invalid UTF-8", "a\xffb", 2, 2},
+	} {
+		actual := RuneOffsetToUTF16(tc.Text, tc.RuneOffset)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+}
+
+func TestUTF16OffsetToRune(t *testing.T) {
+	type TestCase struct {
+		Name string
+
+		Text        string
+		UTF16Offset int
+
+		Expected int
+	}
+
+	for i, tc := range []TestCase{
+		{"Null case", "", 0, 0},
+		{"ASCII", "abc", 2, 2},
+		{"Before an emoji", "a😀b", 1, 1},
+		{"Inside an emoji", "a😀b", 2, 1},
+		{"After an emoji", "a😀b", 3, 2},
+		{"End", "a😀b", 4, 3},
+		{"Past the end", "a😀b", 10, 3},
+		{"Negative", "a😀b", -1, 0},
+	} {
+		actual := UTF16OffsetToRune(tc.Text, tc.UTF16Offset)
+		assert.Equal(t, tc.Expected, actual, fmt.Sprintf("Test case #%d, %s", i, tc.Name))
+	}
+
+	// Both agree with encoding/utf16 and are each other's reverse.
+	text := "x😀日本👍🏽é\U0010FFFFz"
+	runes := []rune(text)
+	for i := 0; i <= len(runes); i++ {
+		offset := RuneOffsetToUTF16(text, i)
+		assert.Equal(t, len(utf16.Encode(runes[:i])), offset, fmt.Sprintf("Rune %d", i))
+		assert.Equal(t, i, UTF16OffsetToRune(text, offset), fmt.Sprintf("Rune %d", i))
+	}
+}